@@ -0,0 +1,179 @@
+package main
+
+import (
+	"math"
+)
+
+type DistanceFunc func(first int, second int) int
+
+type Metric struct {
+	Name     string
+	Distance DistanceFunc
+	// True when pairing both sorted columns is already the minimum,
+	// which holds for any convex function of the difference.
+	SortedIsOptimal bool
+}
+
+var AbsoluteMetric = Metric{Name: "absolute", Distance: AbsoluteDistance, SortedIsOptimal: true}
+var SquaredMetric = Metric{Name: "squared", Distance: SquaredDistance, SortedIsOptimal: true}
+var RelativeMetric = Metric{Name: "relative", Distance: RelativeDistance, SortedIsOptimal: false}
+
+func CappedMetric(limit int) Metric {
+	// Capping makes the cost flat past the limit, sorted pairing is no longer the minimum
+	return Metric{Name: "capped", Distance: CappedDistance(limit), SortedIsOptimal: false}
+}
+
+func AbsoluteDistance(first int, second int) int {
+	return Pair{First: first, Second: second}.Distance()
+}
+
+func SquaredDistance(first int, second int) int {
+	difference := first - second
+	return difference * difference
+}
+
+func RelativeDistance(first int, second int) int {
+	// Difference as a percentage of the largest absolute value, 0 when both are 0
+	largest := max(absolute(first), absolute(second))
+	if largest == 0 {
+		return 0
+	}
+
+	return AbsoluteDistance(first, second) * 100 / largest
+}
+
+func CappedDistance(limit int) DistanceFunc {
+	return func(first int, second int) int {
+		return min(AbsoluteDistance(first, second), limit)
+	}
+}
+
+func MinimumSumOfDistances(firstColumn []int, secondColumn []int, metric Metric) (int, error) {
+	// Returns the lowest possible total distance over every way of pairing the two columns
+	if metric.SortedIsOptimal {
+		pairs, err := PairColumns(firstColumn, secondColumn)
+		if err != nil {
+			return 0, err
+		}
+
+		return sumOfDistancesWith(pairs, metric.Distance), nil
+	}
+
+	if len(firstColumn) != len(secondColumn) {
+		return 0, ColumnLengthError{FirstLength: len(firstColumn), SecondLength: len(secondColumn)}
+	}
+
+	cost := make([][]int, len(firstColumn))
+	for i, first := range firstColumn {
+		cost[i] = make([]int, len(secondColumn))
+		for j, second := range secondColumn {
+			cost[i][j] = metric.Distance(first, second)
+		}
+	}
+
+	_, total := MinCostMatching(cost)
+	return total, nil
+}
+
+func MinCostMatching(cost [][]int) (assignment []int, total int) {
+	// Hungarian algorithm on a square cost matrix, O(n^3).
+	// assignment[row] is the column matched with the row.
+	n := len(cost)
+	if n == 0 {
+		return nil, 0
+	}
+
+	// Potentials and matching are 1-indexed, index 0 is a virtual column
+	rowPotential := make([]int, n+1)
+	columnPotential := make([]int, n+1)
+	matchedRow := make([]int, n+1)
+	way := make([]int, n+1)
+
+	for row := 1; row <= n; row++ {
+		matchedRow[0] = row
+		column := 0
+		minSlack := make([]int, n+1)
+		used := make([]bool, n+1)
+		for j := range minSlack {
+			minSlack[j] = math.MaxInt
+		}
+
+		for matchedRow[column] != 0 {
+			used[column] = true
+			currentRow := matchedRow[column]
+			delta := math.MaxInt
+			nextColumn := 0
+
+			for j := 1; j <= n; j++ {
+				if used[j] {
+					continue
+				}
+
+				slack := cost[currentRow-1][j-1] - rowPotential[currentRow] - columnPotential[j]
+				if slack < minSlack[j] {
+					minSlack[j] = slack
+					way[j] = column
+				}
+				if minSlack[j] < delta {
+					delta = minSlack[j]
+					nextColumn = j
+				}
+			}
+
+			for j := 0; j <= n; j++ {
+				if used[j] {
+					rowPotential[matchedRow[j]] += delta
+					columnPotential[j] -= delta
+				} else {
+					minSlack[j] -= delta
+				}
+			}
+
+			column = nextColumn
+		}
+
+		// Flip the augmenting path
+		for column != 0 {
+			previous := way[column]
+			matchedRow[column] = matchedRow[previous]
+			column = previous
+		}
+	}
+
+	assignment = make([]int, n)
+	for j := 1; j <= n; j++ {
+		assignment[matchedRow[j]-1] = j - 1
+	}
+
+	for row, column := range assignment {
+		total += cost[row][column]
+	}
+
+	return assignment, total
+}
+
+func sumOfDistancesWith(pairs []Pair, distance DistanceFunc) int {
+	var sum int
+	for _, pair := range pairs {
+		sum += pair.DistanceWith(distance)
+	}
+	return sum
+}
+
+func absolute(value int) int {
+	if value < 0 {
+		return -value
+	}
+	return value
+}
+
+func columnsOf(pairs []Pair) (firstColumn []int, secondColumn []int) {
+	firstColumn = make([]int, 0, len(pairs))
+	secondColumn = make([]int, 0, len(pairs))
+	for _, pair := range pairs {
+		firstColumn = append(firstColumn, pair.First)
+		secondColumn = append(secondColumn, pair.Second)
+	}
+
+	return firstColumn, secondColumn
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func bruteForceMinimumSum(firstColumn []int, secondColumn []int, distance DistanceFunc) int {
+	// Try every permutation of the second column
+	best := math.MaxInt
+	used := make([]bool, len(secondColumn))
+
+	var visit func(index int, sum int)
+	visit = func(index int, sum int) {
+		if index == len(firstColumn) {
+			best = min(best, sum)
+			return
+		}
+
+		for j := range secondColumn {
+			if used[j] {
+				continue
+			}
+			used[j] = true
+			visit(index+1, sum+distance(firstColumn[index], secondColumn[j]))
+			used[j] = false
+		}
+	}
+	visit(0, 0)
+
+	return best
+}
+
+func TestDistanceFuncs(t *testing.T) {
+	testValues := func(name string, distance DistanceFunc, first int, second int, expected int) {
+		if distance(first, second) != expected {
+			t.Errorf("Expected %s(%d, %d) to be %d, but got %d", name, first, second, expected, distance(first, second))
+		}
+	}
+
+	testValues("AbsoluteDistance", AbsoluteDistance, 3, 7, 4)
+	testValues("AbsoluteDistance", AbsoluteDistance, 7, 3, 4)
+	testValues("SquaredDistance", SquaredDistance, 3, 7, 16)
+	testValues("RelativeDistance", RelativeDistance, 50, 100, 50)
+	testValues("RelativeDistance", RelativeDistance, 0, 0, 0)
+	testValues("RelativeDistance", RelativeDistance, -4, 4, 200)
+	testValues("CappedDistance", CappedDistance(5), 1, 100, 5)
+	testValues("CappedDistance", CappedDistance(5), 1, 3, 2)
+}
+
+func TestPair_DistanceWith(t *testing.T) {
+	// The absolute distance should match Pair.Distance
+	for _, pair := range GetTestPairs() {
+		if pair.DistanceWith(AbsoluteDistance) != pair.Distance() {
+			t.Errorf("Expected DistanceWith(AbsoluteDistance) to be %d, but got %d", pair.Distance(), pair.DistanceWith(AbsoluteDistance))
+		}
+	}
+}
+
+func TestMinCostMatching(t *testing.T) {
+	// Known 3x3 example: 0->1, 1->0, 2->2 costs 1 + 2 + 2 = 5
+	cost := [][]int{
+		{4, 1, 3},
+		{2, 0, 5},
+		{3, 2, 2},
+	}
+
+	assignment, total := MinCostMatching(cost)
+	if total != 5 {
+		t.Errorf("Expected total to be 5, but got %d", total)
+	}
+
+	seen := make(map[int]bool)
+	sum := 0
+	for row, column := range assignment {
+		if seen[column] {
+			t.Errorf("Expected column %d to be used once, assignment %v", column, assignment)
+		}
+		seen[column] = true
+		sum += cost[row][column]
+	}
+	if sum != total {
+		t.Errorf("Expected assignment cost %d to equal total %d", sum, total)
+	}
+}
+
+func TestMinimumSumOfDistances_MatchesBruteForce(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	metrics := []Metric{AbsoluteMetric, SquaredMetric, RelativeMetric, CappedMetric(3)}
+
+	for round := 0; round < 50; round++ {
+		size := 1 + random.Intn(6)
+		firstColumn := make([]int, size)
+		secondColumn := make([]int, size)
+		for i := 0; i < size; i++ {
+			firstColumn[i] = random.Intn(21) - 5
+			secondColumn[i] = random.Intn(21) - 5
+		}
+
+		for _, metric := range metrics {
+			expected := bruteForceMinimumSum(firstColumn, secondColumn, metric.Distance)
+			sum, err := MinimumSumOfDistances(firstColumn, secondColumn, metric)
+			if err != nil {
+				t.Fatalf("Expected no error, but got %v", err)
+			}
+			if sum != expected {
+				t.Errorf("Expected %s minimum of %v and %v to be %d, but got %d", metric.Name, firstColumn, secondColumn, expected, sum)
+			}
+		}
+	}
+}
+
+func TestMinimumSumOfDistances_CappedIsNotSorted(t *testing.T) {
+	// With a cap of 3, sorted pairing 0->3 and 3->100 gives 3 + 3 = 6,
+	// but 0->100 and 3->3 gives 3 + 0 = 3
+	firstColumn := []int{0, 3}
+	secondColumn := []int{3, 100}
+
+	sum, _ := MinimumSumOfDistances(firstColumn, secondColumn, CappedMetric(3))
+	if sum != 3 {
+		t.Errorf("Expected 3, but got %d", sum)
+	}
+}
+
+func TestCalculateSumOfDistances_WithMetric(t *testing.T) {
+	// Absolute metric gives the same as the sorted pairs, 11
+	pairs := GetPairs(GetFirstColumn(), GetSecondColumn())
+	if CalculateSumOfDistances(pairs, AbsoluteMetric) != 11 {
+		t.Errorf("Expected 11, but got %d", CalculateSumOfDistances(pairs, AbsoluteMetric))
+	}
+
+	// Pairs given in any order are matched again under the metric
+	positional, _ := PairColumns(GetFirstColumn(), GetSecondColumn(), PositionalPairing)
+	if CalculateSumOfDistances(positional, AbsoluteMetric) != 11 {
+		t.Errorf("Expected 11, but got %d", CalculateSumOfDistances(positional, AbsoluteMetric))
+	}
+
+	// Squared: 4 + 1 + 0 + 1 + 4 + 25 = 35
+	if CalculateSumOfDistances(pairs, SquaredMetric) != 35 {
+		t.Errorf("Expected 35, but got %d", CalculateSumOfDistances(pairs, SquaredMetric))
+	}
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+type PairExplanation struct {
+	First        int `json:"first"`
+	Second       int `json:"second"`
+	Distance     int `json:"distance"`
+	RunningTotal int `json:"runningTotal"`
+}
+
+type ValueExplanation struct {
+	Value        int  `json:"value"`
+	Occurrences  int  `json:"occurrences"`
+	Contribution int  `json:"contribution"`
+	Top          bool `json:"top"`
+}
+
+type Explanation struct {
+	Pairs           []PairExplanation  `json:"pairs"`
+	Values          []ValueExplanation `json:"values"`
+	TopContributors []SimilarID        `json:"topContributors"`
+	TotalDistance   int                `json:"totalDistance"`
+	SimilarityScore int                `json:"similarityScore"`
+}
+
+func Explain(firstColumn []int, secondColumn []int, topK int) (Explanation, error) {
+	// Details how FirstPart and SecondPart are reached, one line per pair and one line per left value
+	var explanation Explanation
+
+	pairs, err := PairColumns(firstColumn, secondColumn)
+	if err != nil {
+		return explanation, err
+	}
+
+	for _, pair := range pairs {
+		explanation.TotalDistance += pair.Distance()
+		explanation.Pairs = append(explanation.Pairs, PairExplanation{
+			First:        pair.First,
+			Second:       pair.Second,
+			Distance:     pair.Distance(),
+			RunningTotal: explanation.TotalDistance,
+		})
+	}
+
+	index := NewFrequencyIndex(secondColumn)
+	explanation.TopContributors = index.TopSimilar(firstColumn, topK)
+
+	// Every occurrence of a top contributor is highlighted
+	isTop := make(map[int]bool, len(explanation.TopContributors))
+	for _, id := range explanation.TopContributors {
+		isTop[id.Value] = true
+	}
+
+	for _, value := range firstColumn {
+		occurrences := index.Occurrences(value)
+		contribution := value * occurrences
+		explanation.SimilarityScore += contribution
+		explanation.Values = append(explanation.Values, ValueExplanation{
+			Value:        value,
+			Occurrences:  occurrences,
+			Contribution: contribution,
+			Top:          isTop[value],
+		})
+	}
+
+	return explanation, nil
+}
+
+/************ Explanation methods ************/
+
+func (e Explanation) CSV() (string, error) {
+	// Two tables separated by an empty line: the pairs, then the left values
+	var builder strings.Builder
+
+	writer := csv.NewWriter(&builder)
+	writer.Write([]string{"first", "second", "distance", "running_total"})
+	for _, pair := range e.Pairs {
+		writer.Write([]string{
+			strconv.Itoa(pair.First),
+			strconv.Itoa(pair.Second),
+			strconv.Itoa(pair.Distance),
+			strconv.Itoa(pair.RunningTotal),
+		})
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return "", err
+	}
+
+	builder.WriteString("\n")
+
+	writer = csv.NewWriter(&builder)
+	writer.Write([]string{"value", "occurrences", "contribution", "top"})
+	for _, value := range e.Values {
+		writer.Write([]string{
+			strconv.Itoa(value.Value),
+			strconv.Itoa(value.Occurrences),
+			strconv.Itoa(value.Contribution),
+			strconv.FormatBool(value.Top),
+		})
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return "", err
+	}
+
+	return builder.String(), nil
+}
+
+func (e Explanation) JSON() ([]byte, error) {
+	return json.MarshalIndent(e, "", "  ")
+}
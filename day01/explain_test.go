@@ -0,0 +1,77 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestExplain(t *testing.T) {
+	explanation, err := Explain(GetFirstColumn(), GetSecondColumn(), 1)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+
+	// Totals should match both parts
+	if explanation.TotalDistance != 11 || explanation.SimilarityScore != 31 {
+		t.Errorf("Expected 11 and 31, but got %d and %d", explanation.TotalDistance, explanation.SimilarityScore)
+	}
+
+	// Running totals of the sorted pairs: 2, 3, 3, 4, 6, 11
+	expectedRunningTotals := []int{2, 3, 3, 4, 6, 11}
+	for i, expected := range expectedRunningTotals {
+		if explanation.Pairs[i].RunningTotal != expected {
+			t.Errorf("Expected Pairs[%d].RunningTotal to be %d, but got %d", i, expected, explanation.Pairs[i].RunningTotal)
+		}
+	}
+
+	// Left values in the input order: 3 4 2 1 3 3, contributing 9 4 0 0 9 9
+	expectedContributions := []int{9, 4, 0, 0, 9, 9}
+	for i, expected := range expectedContributions {
+		if explanation.Values[i].Contribution != expected {
+			t.Errorf("Expected Values[%d].Contribution to be %d, but got %d", i, expected, explanation.Values[i].Contribution)
+		}
+
+		// Only 3 is the top contributor
+		if explanation.Values[i].Top != (explanation.Values[i].Value == 3) {
+			t.Errorf("Expected Values[%d].Top to be %t", i, explanation.Values[i].Value == 3)
+		}
+	}
+}
+
+func TestExplanation_CSV(t *testing.T) {
+	explanation, _ := Explain([]int{1, 3}, []int{3, 3}, 1)
+
+	expected := "" +
+		"first,second,distance,running_total\n" +
+		"1,3,2,2\n" +
+		"3,3,0,2\n" +
+		"\n" +
+		"value,occurrences,contribution,top\n" +
+		"1,0,0,false\n" +
+		"3,2,6,true\n"
+
+	output, err := explanation.CSV()
+	if err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+	if output != expected {
+		t.Errorf("Expected\n%s\nbut got\n%s", expected, output)
+	}
+}
+
+func TestExplanation_JSON(t *testing.T) {
+	explanation, _ := Explain(GetFirstColumn(), GetSecondColumn(), 3)
+
+	data, err := explanation.JSON()
+	if err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+
+	var decoded Explanation
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Expected valid JSON, but got %v", err)
+	}
+	if decoded.TotalDistance != 11 || decoded.SimilarityScore != 31 || len(decoded.TopContributors) != 2 {
+		t.Errorf("Expected 11, 31 and 2 top contributors, but got %v", decoded)
+	}
+}
@@ -0,0 +1,321 @@
+package main
+
+import (
+	"bufio"
+	"container/heap"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+type ExternalOptions struct {
+	// Number of values per column kept in memory before a sorted run is written to disk
+	RunSize int
+	// Directory for the temporary run files, the system default when empty
+	TempDir string
+}
+
+type ExternalResult struct {
+	TotalDistance   int
+	SimilarityScore int
+}
+
+type runReader struct {
+	file   *os.File
+	reader *bufio.Reader
+	buffer [8]byte
+}
+
+type mergeItem struct {
+	value int
+	run   int
+}
+
+type mergeHeap []mergeItem
+
+type mergedColumn struct {
+	runs  []*runReader
+	items mergeHeap
+}
+
+const DefaultRunSize = 1 << 20
+
+func SolveExternal(input io.Reader, options ExternalOptions) (ExternalResult, error) {
+	// Same answers as FirstPart and SecondPart, without holding the columns in memory.
+	// Both columns are split into sorted runs on disk, then each column is k-way merged
+	// and both merged columns are walked together once.
+	if options.RunSize <= 0 {
+		options.RunSize = DefaultRunSize
+	}
+
+	directory, err := os.MkdirTemp(options.TempDir, "day01-runs-")
+	if err != nil {
+		return ExternalResult{}, err
+	}
+	defer os.RemoveAll(directory)
+
+	firstRuns, secondRuns, err := writeSortedRuns(input, directory, options.RunSize)
+	if err != nil {
+		return ExternalResult{}, err
+	}
+
+	firstColumn, err := newMergedColumn(firstRuns)
+	if err != nil {
+		return ExternalResult{}, err
+	}
+	defer firstColumn.close()
+
+	secondColumn, err := newMergedColumn(secondRuns)
+	if err != nil {
+		return ExternalResult{}, err
+	}
+	defer secondColumn.close()
+
+	return mergeColumns(firstColumn, secondColumn)
+}
+
+func writeSortedRuns(input io.Reader, directory string, runSize int) (firstRuns []string, secondRuns []string, err error) {
+	firstBuffer := make([]int, 0, runSize)
+	secondBuffer := make([]int, 0, runSize)
+
+	flush := func() error {
+		if len(firstBuffer) == 0 {
+			return nil
+		}
+
+		slices.Sort(firstBuffer)
+		slices.Sort(secondBuffer)
+
+		firstRun, err := writeRun(directory, len(firstRuns)+len(secondRuns), firstBuffer)
+		if err != nil {
+			return err
+		}
+		secondRun, err := writeRun(directory, len(firstRuns)+len(secondRuns)+1, secondBuffer)
+		if err != nil {
+			return err
+		}
+
+		firstRuns = append(firstRuns, firstRun)
+		secondRuns = append(secondRuns, secondRun)
+		firstBuffer = firstBuffer[:0]
+		secondBuffer = secondBuffer[:0]
+		return nil
+	}
+
+	parser := newColumnParser(DefaultReaderOptions())
+	scanner := bufio.NewScanner(input)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		values, err := parser.parseLine(scanner.Text(), lineNumber)
+		if err != nil {
+			return nil, nil, err
+		}
+		if values == nil {
+			continue
+		}
+
+		if len(values) < 2 {
+			return nil, nil, ParseError{Line: lineNumber, Column: 1, Field: scanner.Text(), Err: fmt.Errorf("expected 2 columns, but got %d", len(values))}
+		}
+
+		firstBuffer = append(firstBuffer, values[0])
+		secondBuffer = append(secondBuffer, values[1])
+
+		if len(firstBuffer) == runSize {
+			if err := flush(); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	if err := flush(); err != nil {
+		return nil, nil, err
+	}
+
+	return firstRuns, secondRuns, nil
+}
+
+func writeRun(directory string, number int, values []int) (string, error) {
+	// A run is a sequence of little endian int64 values
+	filename := filepath.Join(directory, fmt.Sprintf("run-%06d", number))
+	file, err := os.Create(filename)
+	if err != nil {
+		return "", err
+	}
+
+	writer := bufio.NewWriter(file)
+	buffer := make([]byte, 8)
+	for _, value := range values {
+		binary.LittleEndian.PutUint64(buffer, uint64(value))
+		if _, err := writer.Write(buffer); err != nil {
+			file.Close()
+			return "", err
+		}
+	}
+
+	if err := writer.Flush(); err != nil {
+		file.Close()
+		return "", err
+	}
+
+	return filename, file.Close()
+}
+
+func mergeColumns(firstColumn *mergedColumn, secondColumn *mergedColumn) (ExternalResult, error) {
+	// Walks every distinct value of both columns in ascending order.
+	// Between two consecutive values, the sorted pairs are apart by the gap times
+	// the difference of how many values each column has seen so far.
+	var result ExternalResult
+	var firstCount, secondCount int
+	var previous int
+	hasPrevious := false
+
+	for {
+		value, ok := nextDistinctValue(firstColumn, secondColumn)
+		if !ok {
+			break
+		}
+
+		if hasPrevious {
+			result.TotalDistance += absolute(firstCount-secondCount) * (value - previous)
+		}
+
+		firstOccurrences, err := firstColumn.consume(value)
+		if err != nil {
+			return ExternalResult{}, err
+		}
+		secondOccurrences, err := secondColumn.consume(value)
+		if err != nil {
+			return ExternalResult{}, err
+		}
+
+		firstCount += firstOccurrences
+		secondCount += secondOccurrences
+		result.SimilarityScore += value * firstOccurrences * secondOccurrences
+
+		previous = value
+		hasPrevious = true
+	}
+
+	if firstCount != secondCount {
+		return ExternalResult{}, ColumnLengthError{FirstLength: firstCount, SecondLength: secondCount}
+	}
+
+	return result, nil
+}
+
+func nextDistinctValue(firstColumn *mergedColumn, secondColumn *mergedColumn) (int, bool) {
+	first, hasFirst := firstColumn.peek()
+	second, hasSecond := secondColumn.peek()
+
+	switch {
+	case hasFirst && hasSecond:
+		return min(first, second), true
+	case hasFirst:
+		return first, true
+	case hasSecond:
+		return second, true
+	default:
+		return 0, false
+	}
+}
+
+func newMergedColumn(filenames []string) (*mergedColumn, error) {
+	column := &mergedColumn{}
+
+	for i, filename := range filenames {
+		file, err := os.Open(filename)
+		if err != nil {
+			column.close()
+			return nil, err
+		}
+
+		run := &runReader{file: file, reader: bufio.NewReader(file)}
+		column.runs = append(column.runs, run)
+
+		value, ok, err := run.next()
+		if err != nil {
+			column.close()
+			return nil, err
+		}
+		if ok {
+			column.items = append(column.items, mergeItem{value: value, run: i})
+		}
+	}
+
+	heap.Init(&column.items)
+	return column, nil
+}
+
+/************ mergedColumn methods ************/
+
+func (c *mergedColumn) peek() (int, bool) {
+	if len(c.items) == 0 {
+		return 0, false
+	}
+	return c.items[0].value, true
+}
+
+func (c *mergedColumn) consume(value int) (int, error) {
+	// Pops every value equal to the given one, returns how many there were
+	count := 0
+	for len(c.items) > 0 && c.items[0].value == value {
+		item := heap.Pop(&c.items).(mergeItem)
+		count++
+
+		next, ok, err := c.runs[item.run].next()
+		if err != nil {
+			return 0, err
+		}
+		if ok {
+			heap.Push(&c.items, mergeItem{value: next, run: item.run})
+		}
+	}
+
+	return count, nil
+}
+
+func (c *mergedColumn) close() {
+	for _, run := range c.runs {
+		run.file.Close()
+	}
+}
+
+/************ runReader methods ************/
+
+func (r *runReader) next() (int, bool, error) {
+	if _, err := io.ReadFull(r.reader, r.buffer[:]); err != nil {
+		if errors.Is(err, io.EOF) {
+			return 0, false, nil
+		}
+		return 0, false, err
+	}
+
+	return int(binary.LittleEndian.Uint64(r.buffer[:])), true, nil
+}
+
+/************ mergeHeap methods ************/
+
+func (h mergeHeap) Len() int           { return len(h) }
+func (h mergeHeap) Less(i, j int) bool { return h[i].value < h[j].value }
+func (h mergeHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+
+func (h *mergeHeap) Push(item any) {
+	*h = append(*h, item.(mergeItem))
+}
+
+func (h *mergeHeap) Pop() any {
+	old := *h
+	item := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return item
+}
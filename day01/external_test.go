@@ -0,0 +1,90 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestSolveExternal(t *testing.T) {
+	// it should return 11 and 31 for the example file, with runs of 2 lines
+	file, err := os.Open(DefaultTestInputFile)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+	defer file.Close()
+
+	result, err := SolveExternal(file, ExternalOptions{RunSize: 2, TempDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+
+	if result.TotalDistance != 11 || result.SimilarityScore != 31 {
+		t.Errorf("Expected 11 and 31, but got %d and %d", result.TotalDistance, result.SimilarityScore)
+	}
+}
+
+func TestSolveExternal_MatchesInMemory(t *testing.T) {
+	random := rand.New(rand.NewSource(6))
+
+	for round := 0; round < 20; round++ {
+		size := random.Intn(200)
+		firstColumn := make([]int, size)
+		secondColumn := make([]int, size)
+
+		var builder strings.Builder
+		for i := 0; i < size; i++ {
+			firstColumn[i] = random.Intn(50) - 10
+			secondColumn[i] = random.Intn(50) - 10
+			fmt.Fprintf(&builder, "%d   %d\n", firstColumn[i], secondColumn[i])
+		}
+
+		runSize := 1 + random.Intn(16)
+		result, err := SolveExternal(strings.NewReader(builder.String()), ExternalOptions{RunSize: runSize, TempDir: t.TempDir()})
+		if err != nil {
+			t.Fatalf("Expected no error, but got %v", err)
+		}
+
+		if result.TotalDistance != FirstPart(firstColumn, secondColumn) {
+			t.Errorf("Expected distance %d, but got %d (run size %d)", FirstPart(firstColumn, secondColumn), result.TotalDistance, runSize)
+		}
+		if result.SimilarityScore != SecondPart(firstColumn, secondColumn) {
+			t.Errorf("Expected score %d, but got %d (run size %d)", SecondPart(firstColumn, secondColumn), result.SimilarityScore, runSize)
+		}
+	}
+}
+
+func TestSolveExternal_CleansTemporaryFiles(t *testing.T) {
+	directory := t.TempDir()
+
+	_, err := SolveExternal(strings.NewReader("1 2\n3 4\n5 6\n"), ExternalOptions{RunSize: 1, TempDir: directory})
+	if err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+
+	entries, _ := os.ReadDir(directory)
+	if len(entries) != 0 {
+		t.Errorf("Expected the temporary directory to be empty, but got %d entries", len(entries))
+	}
+}
+
+func TestSolveExternal_InvalidInput(t *testing.T) {
+	// it should return an error with the line number instead of exiting
+	_, err := SolveExternal(strings.NewReader("1 2\n3 x\n"), ExternalOptions{RunSize: 1, TempDir: t.TempDir()})
+	if err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("Expected an error on line 2, but got %v", err)
+	}
+
+	_, err = SolveExternal(strings.NewReader("1 2\n3\n"), ExternalOptions{RunSize: 1, TempDir: t.TempDir()})
+	if err == nil {
+		t.Errorf("Expected an error for a missing column")
+	}
+
+	var lengthError ColumnLengthError
+	if errors.As(err, &lengthError) {
+		t.Errorf("Expected a parse error, not a ColumnLengthError")
+	}
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"cmp"
+	"slices"
+)
+
+type FrequencyIndex struct {
+	counts map[int]int
+}
+
+type SimilarID struct {
+	Value       int `json:"value"`
+	Occurrences int `json:"occurrences"`
+	Score       int `json:"score"`
+}
+
+func NewFrequencyIndex(column []int) FrequencyIndex {
+	// Count every value once, so lookups are O(1) afterwards
+	counts := make(map[int]int, len(column))
+	for _, value := range column {
+		counts[value]++
+	}
+
+	return FrequencyIndex{counts: counts}
+}
+
+/************ FrequencyIndex methods ************/
+
+func (f FrequencyIndex) Occurrences(value int) int {
+	return f.counts[value]
+}
+
+func (f FrequencyIndex) SimilarityScore(column []int) int {
+	// Each value of the column is multiplied by the number of times it appears in the index
+	var score int
+	for _, value := range column {
+		score += value * f.Occurrences(value)
+	}
+	return score
+}
+
+func (f FrequencyIndex) TopSimilar(column []int, n int) []SimilarID {
+	// Group the column by value, the score of an ID is its whole contribution to the similarity score
+	leftCounts := NewFrequencyIndex(column)
+
+	var ids []SimilarID
+	for value, leftCount := range leftCounts.counts {
+		occurrences := f.Occurrences(value)
+		if occurrences == 0 {
+			continue
+		}
+
+		ids = append(ids, SimilarID{
+			Value:       value,
+			Occurrences: occurrences,
+			Score:       value * occurrences * leftCount,
+		})
+	}
+
+	// Highest score first, lowest value first on ties so the result is stable
+	slices.SortFunc(ids, func(a, b SimilarID) int {
+		if a.Score != b.Score {
+			return cmp.Compare(b.Score, a.Score)
+		}
+		return cmp.Compare(a.Value, b.Value)
+	})
+
+	if n >= 0 && n < len(ids) {
+		ids = ids[:n]
+	}
+
+	return ids
+}
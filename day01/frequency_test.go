@@ -0,0 +1,81 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestFrequencyIndex_Occurrences(t *testing.T) {
+	// it should return 3 for 3, 1 for 4, 1 for 5, 1 for 9 and 0 for missing values
+	index := NewFrequencyIndex(GetSecondColumn())
+
+	expectedOccurrences := map[int]int{3: 3, 4: 1, 5: 1, 9: 1, 1: 0, 2: 0}
+	for value, expected := range expectedOccurrences {
+		if index.Occurrences(value) != expected {
+			t.Errorf("Expected Occurrences(%d) to be %d, but got %d", value, expected, index.Occurrences(value))
+		}
+	}
+}
+
+func TestFrequencyIndex_SimilarityScore(t *testing.T) {
+	// it should return 31, the same as SecondPart
+	var ExpectedScore = 31
+
+	index := NewFrequencyIndex(GetSecondColumn())
+	score := index.SimilarityScore(GetFirstColumn())
+
+	if score != ExpectedScore {
+		t.Errorf("Expected SimilarityScore to be %d, but got %d", ExpectedScore, score)
+	}
+
+	if score != SecondPart(GetFirstColumn(), GetSecondColumn()) {
+		t.Errorf("Expected SimilarityScore to match SecondPart, but got %d", score)
+	}
+}
+
+func TestFrequencyIndex_SimilarityScore_MatchesLinearScan(t *testing.T) {
+	// it should return the same score as the naive O(n*m) scan
+	firstColumn := []int{7, 7, 1, 0, -2, 5, 5, 5, 12}
+	secondColumn := []int{5, 7, -2, -2, 12, 12, 12, 8, 0}
+
+	expected := 0
+	for _, value := range firstColumn {
+		for _, other := range secondColumn {
+			if value == other {
+				expected += value
+			}
+		}
+	}
+
+	score := NewFrequencyIndex(secondColumn).SimilarityScore(firstColumn)
+	if score != expected {
+		t.Errorf("Expected SimilarityScore to be %d, but got %d", expected, score)
+	}
+}
+
+func TestFrequencyIndex_TopSimilar(t *testing.T) {
+	// Left column holds 3 three times and 4 once, right column holds 3 three times and 4 once.
+	// 3 contributes 3*3*3 = 27, 4 contributes 4*1*1 = 4, 2 and 1 are absent from the right column.
+	expected := []SimilarID{
+		{Value: 3, Occurrences: 3, Score: 27},
+		{Value: 4, Occurrences: 1, Score: 4},
+	}
+
+	index := NewFrequencyIndex(GetSecondColumn())
+	ids := index.TopSimilar(GetFirstColumn(), 10)
+
+	if len(ids) != len(expected) {
+		t.Fatalf("Expected %d ids, but got %d", len(expected), len(ids))
+	}
+
+	for i := range expected {
+		if ids[i] != expected[i] {
+			t.Errorf("Expected ids[%d] to be %v, but got %v", i, expected[i], ids[i])
+		}
+	}
+
+	// it should only return the first one when n = 1
+	ids = index.TopSimilar(GetFirstColumn(), 1)
+	if len(ids) != 1 || ids[0] != expected[0] {
+		t.Errorf("Expected [%v], but got %v", expected[0], ids)
+	}
+}
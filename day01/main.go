@@ -1,11 +1,10 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"os"
 	"slices"
-	"strconv"
-	"strings"
 )
 
 type Pair struct {
@@ -13,16 +12,161 @@ type Pair struct {
 	Second int
 }
 
+type PairingStrategy int
+
+const (
+	// Both columns sorted lowest to highest, the puzzle's pairing
+	SortedPairing PairingStrategy = iota
+	// Line by line, as the columns were read
+	PositionalPairing
+	// First column sorted lowest to highest, second column highest to lowest
+	ReverseSortedPairing
+)
+
+type ColumnLengthError struct {
+	FirstLength  int
+	SecondLength int
+}
+
 func main() {
+	matrixFormat := flag.String("matrix", "", "print the pairwise matrix of every column, as table or json")
+	explainFormat := flag.String("explain", "", "print the contribution of every pair and left value, as csv or json")
+	topK := flag.Int("top", 3, "number of top similarity contributors highlighted by -explain")
+	externalRunSize := flag.Int("external", 0, "solve with sorted runs on disk, keeping this many values per column in memory")
+	reconcile := flag.Bool("reconcile", false, "print the values found only in one list, or with different counts")
+	overflowMode := flag.String("overflow", "error", "what to do when a total overflows, error to stop or big to switch to math/big")
+	flag.Parse()
+
+	if *externalRunSize > 0 {
+		printExternalSolution("inputs.txt", *externalRunSize)
+		return
+	}
+
+	if *matrixFormat != "" {
+		printColumnMatrix(LoadColumns("inputs.txt"), *matrixFormat)
+		return
+	}
+
 	// print inputs
 	firstColumn, secondColumn := LoadInputs("inputs.txt")
 
-	// print first part solution
-	fmt.Println("First part solution: ", FirstPart(firstColumn, secondColumn))
-	fmt.Println("Second part solution: ", SecondPart(firstColumn, secondColumn))
+	// Reconciling is a multiset diff, it works on lists of different lengths
+	if *reconcile {
+		fmt.Print(NewReconciliationReport(firstColumn, secondColumn).Text())
+		return
+	}
+
+	if _, err := PairColumns(firstColumn, secondColumn); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	if *explainFormat != "" {
+		printExplanation(firstColumn, secondColumn, *topK, *explainFormat)
+		return
+	}
+
+	// print both solutions, overflows are always detected
+	printCheckedSolution(firstColumn, secondColumn, *overflowMode)
+}
+
+func printExternalSolution(filename string, runSize int) {
+	file, err := os.Open(filename)
+	if err != nil {
+		fmt.Println("Error reading file")
+		os.Exit(1)
+	}
+	defer file.Close()
+
+	result, err := SolveExternal(file, ExternalOptions{RunSize: runSize})
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	fmt.Println("First part solution: ", result.TotalDistance)
+	fmt.Println("Second part solution: ", result.SimilarityScore)
+}
+
+func printCheckedSolution(firstColumn []int, secondColumn []int, overflowMode string) {
+	var mode OverflowMode
+	switch overflowMode {
+	case "error":
+		mode = ReturnOverflowError
+	case "big":
+		mode = PromoteToBigInt
+	default:
+		fmt.Println("Unknown overflow mode:", overflowMode)
+		os.Exit(1)
+	}
+
+	sum, err := CheckedSumOfDistances(GetPairs(firstColumn, secondColumn), mode)
+	if err != nil {
+		fmt.Println("First part:", err)
+		os.Exit(1)
+	}
+
+	score, err := CheckedSimilarityScore(firstColumn, secondColumn, mode)
+	if err != nil {
+		fmt.Println("Second part:", err)
+		os.Exit(1)
+	}
+
+	fmt.Println("First part solution: ", sum)
+	fmt.Println("Second part solution: ", score)
+}
+
+func printColumnMatrix(columns [][]int, format string) {
+	matrix, err := NewColumnMatrix(columns)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	switch format {
+	case "table":
+		fmt.Print(matrix.Table())
+	case "json":
+		data, err := matrix.JSON()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		fmt.Println(string(data))
+	default:
+		fmt.Println("Unknown matrix format:", format)
+		os.Exit(1)
+	}
+}
+
+func printExplanation(firstColumn []int, secondColumn []int, topK int, format string) {
+	explanation, err := Explain(firstColumn, secondColumn, topK)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	var output string
+	switch format {
+	case "csv":
+		output, err = explanation.CSV()
+	case "json":
+		var data []byte
+		data, err = explanation.JSON()
+		output = string(data) + "\n"
+	default:
+		err = fmt.Errorf("unknown explain format: %s", format)
+	}
+
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	fmt.Print(output)
 }
 
 func FirstPart(firstColumn []int, secondColumn []int) int {
+	// Panics like GetPairs when the columns have different lengths
 	pairs := GetPairs(firstColumn, secondColumn)
 	return CalculateSumOfDistances(pairs)
 }
@@ -33,59 +177,86 @@ func SecondPart(firstColumn []int, secondColumn []int) int {
 
 func LoadInputs(filename string) (firstColumn []int, secondColumn []int) {
 	// reading inputs.txt as 2 arrays of integers, one for each column
-	data, err := os.ReadFile(filename)
-	if err != nil {
-		fmt.Println("Error reading file")
+	columns := LoadColumns(filename)
+	if len(columns) < 2 {
+		fmt.Println("Expected at least 2 columns")
 		os.Exit(1)
 	}
 
-	lines := strings.Split(string(data), "\n")
-
-	for _, line := range lines {
-		if line == "" {
-			continue
-		}
+	return columns[0], columns[1]
+}
 
-		// It should split "3   4\r" into ["3", "4"]
-		// and then convert them to integers
-		columns := strings.Fields(line)
-		first, err := strconv.Atoi(columns[0])
-		if err != nil {
-			fmt.Println("Error converting to integer")
-			os.Exit(1)
-		}
+func LoadColumns(filename string) (columns [][]int) {
+	// reading the inputs as one array of integers per column, any number of columns
+	// CSV, TSV, headers and comments are handled by ReadColumns
+	columns, err := LoadColumnsWithOptions(filename, DefaultReaderOptions())
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
 
-		second, err := strconv.Atoi(columns[1])
-		if err != nil {
-			fmt.Println("Error converting to integer")
-			os.Exit(1)
-		}
+	return columns
+}
 
-		firstColumn = append(firstColumn, first)
-		secondColumn = append(secondColumn, second)
+func GetPairs(firstColumn []int, secondColumn []int) []Pair {
+	// Sorted pairing, the columns of the caller are left untouched.
+	// Panics with a ColumnLengthError when the columns can't be paired, a sum of distances
+	// over no pair would look like a valid answer. Use PairColumns to get the error instead.
+	pairs, err := PairColumns(firstColumn, secondColumn)
+	if err != nil {
+		panic(err)
 	}
 
-	return firstColumn, secondColumn
+	return pairs
 }
 
-func GetPairs(firstColumn []int, secondColumn []int) []Pair {
+func PairColumns(firstColumn []int, secondColumn []int, strategy ...PairingStrategy) ([]Pair, error) {
+	// Sorted pairing is used when no strategy is given
+	if len(firstColumn) != len(secondColumn) {
+		return nil, ColumnLengthError{FirstLength: len(firstColumn), SecondLength: len(secondColumn)}
+	}
+
+	selectedStrategy := SortedPairing
+	if len(strategy) > 0 {
+		selectedStrategy = strategy[0]
+	}
 
-	// Sort the two arrays lowest to highest int.
-	slices.Sort(firstColumn)
-	slices.Sort(secondColumn)
+	// Work on copies, so the caller can still use its columns in the original order
+	first := slices.Clone(firstColumn)
+	second := slices.Clone(secondColumn)
 
-	// it should return an array of Pair
-	var pairs []Pair
-	for i := 0; i < len(firstColumn); i++ {
-		pair := Pair{First: firstColumn[i], Second: secondColumn[i]}
-		pairs = append(pairs, pair)
+	switch selectedStrategy {
+	case SortedPairing:
+		slices.Sort(first)
+		slices.Sort(second)
+	case PositionalPairing:
+		// Nothing to do, lines are already aligned
+	case ReverseSortedPairing:
+		slices.Sort(first)
+		slices.Sort(second)
+		slices.Reverse(second)
+	default:
+		return nil, fmt.Errorf("unknown pairing strategy %d", selectedStrategy)
 	}
 
-	return pairs
+	pairs := make([]Pair, 0, len(first))
+	for i := 0; i < len(first); i++ {
+		pairs = append(pairs, Pair{First: first[i], Second: second[i]})
+	}
+
+	return pairs, nil
 }
 
-func CalculateSumOfDistances(pairs []Pair) int {
-	// returns the sum of all distances
+func CalculateSumOfDistances(pairs []Pair, metric ...Metric) int {
+	// returns the sum of all distances, wrapping on overflow like any int sum.
+	// CheckedSumOfDistances detects overflows.
+	// When a metric is given, the values of the pairs are matched again to get the true minimum under that metric.
+	if len(metric) > 0 {
+		firstColumn, secondColumn := columnsOf(pairs)
+		sum, _ := MinimumSumOfDistances(firstColumn, secondColumn, metric[0])
+		return sum
+	}
+
 	var sum int
 	for _, pair := range pairs {
 		sum += pair.Distance()
@@ -94,7 +265,8 @@ func CalculateSumOfDistances(pairs []Pair) int {
 }
 
 func CountOccurrences(value int, column []int) int {
-	// returns the number of times a value appears in the column
+	// returns the number of times a value appears in the column.
+	// A single lookup is a plain scan, FrequencyIndex answers repeated lookups.
 	var count int
 	for _, v := range column {
 		if v == value {
@@ -106,11 +278,15 @@ func CountOccurrences(value int, column []int) int {
 }
 
 func CalculateSimilarityScore(firstColumn []int, secondColumn []int) int {
-	var score int
-	for _, value := range firstColumn {
-		score += value * CountOccurrences(value, secondColumn)
-	}
-	return score
+	// The right column is indexed once, instead of being scanned for every left value.
+	// Wraps on overflow, CheckedSimilarityScore detects it.
+	return NewFrequencyIndex(secondColumn).SimilarityScore(firstColumn)
+}
+
+/************ ColumnLengthError methods ************/
+
+func (e ColumnLengthError) Error() string {
+	return fmt.Sprintf("columns have different lengths: %d and %d", e.FirstLength, e.SecondLength)
 }
 
 /************ Pair methods ************/
@@ -124,3 +300,7 @@ func (p Pair) Distance() int {
 		return p.Second - p.First
 	}
 }
+
+func (p Pair) DistanceWith(distance DistanceFunc) int {
+	return distance(p.First, p.Second)
+}
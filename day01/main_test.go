@@ -1,6 +1,8 @@
 package main
 
 import (
+	"errors"
+	"slices"
 	"testing"
 )
 
@@ -64,7 +66,14 @@ func TestGetPairs(t *testing.T) {
 	// 3, 5
 	// 4, 9
 
-	var ExpectedPairs = GetTestPairs()
+	var ExpectedPairs = []Pair{
+		{1, 3},
+		{2, 3},
+		{3, 3},
+		{3, 4},
+		{3, 5},
+		{4, 9},
+	}
 
 	firstColumn := GetFirstColumn()
 	secondColumn := GetSecondColumn()
@@ -77,6 +86,90 @@ func TestGetPairs(t *testing.T) {
 	}
 }
 
+func TestGetPairs_ShouldNotModifyColumns(t *testing.T) {
+	// Sorting happens on copies, so the columns keep their original order
+	firstColumn := GetFirstColumn()
+	secondColumn := GetSecondColumn()
+
+	GetPairs(firstColumn, secondColumn)
+
+	if !slices.Equal(firstColumn, GetFirstColumn()) {
+		t.Errorf("Expected firstColumn to be %v, but got %v", GetFirstColumn(), firstColumn)
+	}
+	if !slices.Equal(secondColumn, GetSecondColumn()) {
+		t.Errorf("Expected secondColumn to be %v, but got %v", GetSecondColumn(), secondColumn)
+	}
+}
+
+func TestPairColumns_Strategies(t *testing.T) {
+	testValues := func(strategy PairingStrategy, expected []Pair) {
+		pairs, err := PairColumns(GetFirstColumn(), GetSecondColumn(), strategy)
+		if err != nil {
+			t.Fatalf("Expected no error, but got %v", err)
+		}
+		if !slices.Equal(pairs, expected) {
+			t.Errorf("Expected strategy %d to return %v, but got %v", strategy, expected, pairs)
+		}
+	}
+
+	testValues(SortedPairing, []Pair{{1, 3}, {2, 3}, {3, 3}, {3, 4}, {3, 5}, {4, 9}})
+	testValues(PositionalPairing, []Pair{{3, 4}, {4, 3}, {2, 5}, {1, 3}, {3, 9}, {3, 3}})
+	testValues(ReverseSortedPairing, []Pair{{1, 9}, {2, 5}, {3, 4}, {3, 3}, {3, 3}, {4, 3}})
+
+	// No strategy is the same as the sorted one
+	pairs, _ := PairColumns(GetFirstColumn(), GetSecondColumn())
+	if !slices.Equal(pairs, GetPairs(GetFirstColumn(), GetSecondColumn())) {
+		t.Errorf("Expected default strategy to be sorted, but got %v", pairs)
+	}
+}
+
+func TestPairColumns_DifferentLengths(t *testing.T) {
+	// it should return a ColumnLengthError instead of panicking
+	_, err := PairColumns([]int{1, 2, 3}, []int{1, 2})
+
+	var lengthError ColumnLengthError
+	if !errors.As(err, &lengthError) {
+		t.Fatalf("Expected a ColumnLengthError, but got %v", err)
+	}
+	if lengthError.FirstLength != 3 || lengthError.SecondLength != 2 {
+		t.Errorf("Expected lengths 3 and 2, but got %d and %d", lengthError.FirstLength, lengthError.SecondLength)
+	}
+}
+
+func TestFirstPart_DifferentLengths(t *testing.T) {
+	// GetPairs and FirstPart must not answer 0 for columns that can't be paired
+	testPanics := func(name string, call func()) {
+		defer func() {
+			var lengthError ColumnLengthError
+			err, _ := recover().(error)
+			if !errors.As(err, &lengthError) {
+				t.Errorf("Expected %s to panic with a ColumnLengthError, but got %v", name, err)
+			}
+		}()
+		call()
+	}
+
+	testPanics("GetPairs", func() { GetPairs([]int{1, 2, 3}, []int{1, 2}) })
+	testPanics("FirstPart", func() { FirstPart([]int{1, 2, 3}, []int{1, 2}) })
+}
+
+func TestFirstPartAndSecondPart_AnyOrder(t *testing.T) {
+	// Running one part must not change the result of the other
+	firstColumn := GetFirstColumn()
+	secondColumn := GetSecondColumn()
+
+	second := SecondPart(firstColumn, secondColumn)
+	first := FirstPart(firstColumn, secondColumn)
+	secondAgain := SecondPart(firstColumn, secondColumn)
+
+	if first != 11 || second != 31 || secondAgain != 31 {
+		t.Errorf("Expected 11 and 31, but got %d, %d and %d", first, second, secondAgain)
+	}
+	if !slices.Equal(firstColumn, GetFirstColumn()) {
+		t.Errorf("Expected firstColumn to be untouched, but got %v", firstColumn)
+	}
+}
+
 func TestPair_Distance(t *testing.T) {
 	// it should return 2, 1, 0, 1, 2, 5
 	var ExpectedDistances = []int{2, 1, 0, 1, 2, 5}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+)
+
+type ColumnMatrix struct {
+	// Distances[i][j] is the total distance between column i and column j, it is symmetric.
+	// Similarities[i][j] is the similarity score of column i against column j, it is not.
+	Distances    [][]int `json:"distances"`
+	Similarities [][]int `json:"similarities"`
+}
+
+func NewColumnMatrix(columns [][]int) (ColumnMatrix, error) {
+	size := len(columns)
+	matrix := ColumnMatrix{
+		Distances:    make([][]int, size),
+		Similarities: make([][]int, size),
+	}
+
+	// Every column is indexed once, and reused against all the others
+	indexes := make([]FrequencyIndex, size)
+	for i, column := range columns {
+		indexes[i] = NewFrequencyIndex(column)
+	}
+
+	for i := 0; i < size; i++ {
+		matrix.Distances[i] = make([]int, size)
+		matrix.Similarities[i] = make([]int, size)
+	}
+
+	for i := 0; i < size; i++ {
+		for j := 0; j < size; j++ {
+			matrix.Similarities[i][j] = indexes[j].SimilarityScore(columns[i])
+
+			if j <= i {
+				continue
+			}
+
+			pairs, err := PairColumns(columns[i], columns[j])
+			if err != nil {
+				return ColumnMatrix{}, err
+			}
+
+			distance := CalculateSumOfDistances(pairs)
+			matrix.Distances[i][j] = distance
+			matrix.Distances[j][i] = distance
+		}
+	}
+
+	return matrix, nil
+}
+
+/************ ColumnMatrix methods ************/
+
+func (m ColumnMatrix) Table() string {
+	// Renders both matrices as aligned text tables, columns are numbered from 1
+	var builder strings.Builder
+
+	m.writeTable(&builder, "Total distance", m.Distances)
+	builder.WriteString("\n")
+	m.writeTable(&builder, "Similarity score", m.Similarities)
+
+	return builder.String()
+}
+
+func (m ColumnMatrix) JSON() ([]byte, error) {
+	return json.MarshalIndent(m, "", "  ")
+}
+
+func (m ColumnMatrix) writeTable(builder *strings.Builder, title string, values [][]int) {
+	writer := tabwriter.NewWriter(builder, 0, 0, 2, ' ', tabwriter.AlignRight)
+
+	fmt.Fprint(writer, title, "\t")
+	for j := range values {
+		fmt.Fprintf(writer, "%d\t", j+1)
+	}
+	fmt.Fprintln(writer)
+
+	for i, row := range values {
+		fmt.Fprintf(writer, "%d\t", i+1)
+		for _, value := range row {
+			fmt.Fprintf(writer, "%d\t", value)
+		}
+		fmt.Fprintln(writer)
+	}
+
+	writer.Flush()
+}
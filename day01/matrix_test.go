@@ -0,0 +1,100 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+const ColumnsTestInputFile = "inputs.txt.columns.example"
+
+func TestLoadColumns(t *testing.T) {
+	// it should return 3 columns from the inputs.txt.columns.example file
+	expectedColumns := [][]int{
+		{3, 4, 2, 1, 3, 3},
+		{4, 3, 5, 3, 9, 3},
+		{1, 2, 3, 3, 4, 9},
+	}
+
+	columns := LoadColumns(ColumnsTestInputFile)
+
+	if len(columns) != len(expectedColumns) {
+		t.Fatalf("Expected %d columns, but got %d", len(expectedColumns), len(columns))
+	}
+
+	for i := range expectedColumns {
+		for j := range expectedColumns[i] {
+			if columns[i][j] != expectedColumns[i][j] {
+				t.Errorf("Expected columns[%d][%d] to be %d, but got %d", i, j, expectedColumns[i][j], columns[i][j])
+			}
+		}
+	}
+}
+
+func TestNewColumnMatrix(t *testing.T) {
+	matrix, err := NewColumnMatrix(LoadColumns(ColumnsTestInputFile))
+	if err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+
+	// Columns 1 and 2 are the puzzle example: distance 11, similarity 31
+	if matrix.Distances[0][1] != 11 || matrix.Distances[1][0] != 11 {
+		t.Errorf("Expected distance between 1 and 2 to be 11, but got %v", matrix.Distances)
+	}
+	if matrix.Similarities[0][1] != 31 {
+		t.Errorf("Expected similarity of 1 against 2 to be 31, but got %d", matrix.Similarities[0][1])
+	}
+
+	// Column 3 sorted is 1 2 3 3 4 9, against 1 2 3 3 3 4 it is 0+0+0+0+1+5 = 6
+	if matrix.Distances[0][2] != 6 {
+		t.Errorf("Expected distance between 1 and 3 to be 6, but got %d", matrix.Distances[0][2])
+	}
+
+	// A column is at distance 0 from itself
+	for i := range matrix.Distances {
+		if matrix.Distances[i][i] != 0 {
+			t.Errorf("Expected Distances[%d][%d] to be 0, but got %d", i, i, matrix.Distances[i][i])
+		}
+	}
+}
+
+func TestNewColumnMatrix_DifferentLengths(t *testing.T) {
+	_, err := NewColumnMatrix([][]int{{1, 2}, {1}})
+	if err == nil {
+		t.Errorf("Expected an error for columns of different lengths")
+	}
+}
+
+func TestColumnMatrix_Table(t *testing.T) {
+	matrix, _ := NewColumnMatrix([][]int{GetFirstColumn(), GetSecondColumn()})
+
+	// 3 appears three times in both columns, 3*3*3 + 4 + 2 + 1 = 34 for the first one against itself
+	expected := "" +
+		"  Total distance   1   2\n" +
+		"               1   0  11\n" +
+		"               2  11   0\n" +
+		"\n" +
+		"  Similarity score   1   2\n" +
+		"                 1  34  31\n" +
+		"                 2  31  45\n"
+
+	if matrix.Table() != expected {
+		t.Errorf("Expected table\n%s\nbut got\n%s", expected, matrix.Table())
+	}
+}
+
+func TestColumnMatrix_JSON(t *testing.T) {
+	matrix, _ := NewColumnMatrix([][]int{GetFirstColumn(), GetSecondColumn()})
+
+	data, err := matrix.JSON()
+	if err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+
+	var decoded ColumnMatrix
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Expected valid JSON, but got %v", err)
+	}
+	if decoded.Distances[0][1] != 11 || decoded.Similarities[0][1] != 31 {
+		t.Errorf("Expected 11 and 31, but got %v", decoded)
+	}
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"errors"
+	"math"
+	"math/big"
+)
+
+type OverflowMode int
+
+const (
+	// Stop and return ErrOverflow as soon as a total doesn't fit in an int
+	ReturnOverflowError OverflowMode = iota
+	// Keep going with math/big once a total doesn't fit in an int
+	PromoteToBigInt
+)
+
+var ErrOverflow = errors.New("integer overflow")
+
+type checkedAccumulator struct {
+	mode  OverflowMode
+	small int
+	// Only set once the total has overflowed in PromoteToBigInt mode
+	large *big.Int
+}
+
+func CheckedSumOfDistances(pairs []Pair, mode OverflowMode) (*big.Int, error) {
+	// Same as CalculateSumOfDistances, but overflows are detected
+	accumulator := checkedAccumulator{mode: mode}
+
+	for _, pair := range pairs {
+		if distance, ok := checkedDistance(pair); ok {
+			if err := accumulator.add(distance); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		// The distance alone doesn't fit, e.g. MaxInt against MinInt
+		distance := new(big.Int).Sub(big.NewInt(int64(pair.First)), big.NewInt(int64(pair.Second)))
+		if err := accumulator.addBig(distance.Abs(distance)); err != nil {
+			return nil, err
+		}
+	}
+
+	return accumulator.result(), nil
+}
+
+func CheckedSimilarityScore(firstColumn []int, secondColumn []int, mode OverflowMode) (*big.Int, error) {
+	// Same as CalculateSimilarityScore, but overflows are detected
+	index := NewFrequencyIndex(secondColumn)
+	accumulator := checkedAccumulator{mode: mode}
+
+	for _, value := range firstColumn {
+		occurrences := index.Occurrences(value)
+		if contribution, ok := checkedMultiply(value, occurrences); ok {
+			if err := accumulator.add(contribution); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		contribution := new(big.Int).Mul(big.NewInt(int64(value)), big.NewInt(int64(occurrences)))
+		if err := accumulator.addBig(contribution); err != nil {
+			return nil, err
+		}
+	}
+
+	return accumulator.result(), nil
+}
+
+func checkedAdd(a int, b int) (int, bool) {
+	sum := a + b
+	// Overflow happened when both have the same sign and the sum doesn't
+	if (a >= 0) == (b >= 0) && (sum >= 0) != (a >= 0) {
+		return 0, false
+	}
+	return sum, true
+}
+
+func checkedMultiply(a int, b int) (int, bool) {
+	if a == 0 || b == 0 {
+		return 0, true
+	}
+	if (a == -1 && b == math.MinInt) || (b == -1 && a == math.MinInt) {
+		return 0, false
+	}
+
+	product := a * b
+	if product/b != a {
+		return 0, false
+	}
+	return product, true
+}
+
+func checkedDistance(pair Pair) (int, bool) {
+	difference, ok := checkedAdd(pair.First, -pair.Second)
+	if !ok || pair.Second == math.MinInt || difference == math.MinInt {
+		return 0, false
+	}
+
+	return absolute(difference), true
+}
+
+/************ checkedAccumulator methods ************/
+
+func (a *checkedAccumulator) add(value int) error {
+	if a.large != nil {
+		a.large.Add(a.large, big.NewInt(int64(value)))
+		return nil
+	}
+
+	if sum, ok := checkedAdd(a.small, value); ok {
+		a.small = sum
+		return nil
+	}
+
+	return a.addBig(big.NewInt(int64(value)))
+}
+
+func (a *checkedAccumulator) addBig(value *big.Int) error {
+	if a.mode == ReturnOverflowError {
+		// The value may still fit once added to the current total
+		sum := new(big.Int).Add(big.NewInt(int64(a.small)), value)
+		if !sum.IsInt64() {
+			return ErrOverflow
+		}
+		a.small = int(sum.Int64())
+		return nil
+	}
+
+	if a.large == nil {
+		a.large = big.NewInt(int64(a.small))
+	}
+	a.large.Add(a.large, value)
+	return nil
+}
+
+func (a *checkedAccumulator) result() *big.Int {
+	if a.large != nil {
+		return a.large
+	}
+	return big.NewInt(int64(a.small))
+}
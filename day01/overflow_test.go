@@ -0,0 +1,111 @@
+package main
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"testing"
+)
+
+func TestCheckedSumOfDistances(t *testing.T) {
+	// Small values give the same as CalculateSumOfDistances
+	pairs := GetPairs(GetFirstColumn(), GetSecondColumn())
+	for _, mode := range []OverflowMode{ReturnOverflowError, PromoteToBigInt} {
+		sum, err := CheckedSumOfDistances(pairs, mode)
+		if err != nil || sum.Cmp(big.NewInt(11)) != 0 {
+			t.Errorf("Expected 11, but got %v (%v)", sum, err)
+		}
+	}
+}
+
+func TestCheckedSumOfDistances_Overflow(t *testing.T) {
+	// Two distances of MaxInt - 1 don't fit in an int
+	pairs := []Pair{{math.MaxInt64, 1}, {1, math.MaxInt64}}
+
+	_, err := CheckedSumOfDistances(pairs, ReturnOverflowError)
+	if !errors.Is(err, ErrOverflow) {
+		t.Errorf("Expected ErrOverflow, but got %v", err)
+	}
+
+	expected := new(big.Int).Mul(big.NewInt(math.MaxInt64-1), big.NewInt(2))
+	sum, err := CheckedSumOfDistances(pairs, PromoteToBigInt)
+	if err != nil || sum.Cmp(expected) != 0 {
+		t.Errorf("Expected %v, but got %v (%v)", expected, sum, err)
+	}
+}
+
+func TestCheckedSumOfDistances_DistanceOverflow(t *testing.T) {
+	// The distance between MaxInt and MinInt doesn't fit in an int by itself
+	pairs := []Pair{{math.MinInt64, math.MaxInt64}}
+
+	_, err := CheckedSumOfDistances(pairs, ReturnOverflowError)
+	if !errors.Is(err, ErrOverflow) {
+		t.Errorf("Expected ErrOverflow, but got %v", err)
+	}
+
+	expected, _ := new(big.Int).SetString("18446744073709551615", 10)
+	sum, err := CheckedSumOfDistances(pairs, PromoteToBigInt)
+	if err != nil || sum.Cmp(expected) != 0 {
+		t.Errorf("Expected %v, but got %v (%v)", expected, sum, err)
+	}
+}
+
+func TestCheckedSimilarityScore(t *testing.T) {
+	score, err := CheckedSimilarityScore(GetFirstColumn(), GetSecondColumn(), ReturnOverflowError)
+	if err != nil || score.Cmp(big.NewInt(31)) != 0 {
+		t.Errorf("Expected 31, but got %v (%v)", score, err)
+	}
+
+	// A value close to MaxInt appearing twice on the right overflows the contribution
+	value := math.MaxInt64 - 7
+	firstColumn := []int{value}
+	secondColumn := []int{value, value}
+
+	_, err = CheckedSimilarityScore(firstColumn, secondColumn, ReturnOverflowError)
+	if !errors.Is(err, ErrOverflow) {
+		t.Errorf("Expected ErrOverflow, but got %v", err)
+	}
+
+	expected := new(big.Int).Mul(big.NewInt(int64(value)), big.NewInt(2))
+	score, err = CheckedSimilarityScore(firstColumn, secondColumn, PromoteToBigInt)
+	if err != nil || score.Cmp(expected) != 0 {
+		t.Errorf("Expected %v, but got %v (%v)", expected, score, err)
+	}
+}
+
+func TestCheckedSimilarityScore_SumOverflow(t *testing.T) {
+	// Each contribution fits, the total of both doesn't
+	value := math.MaxInt64/2 + 1
+	firstColumn := []int{value, value}
+	secondColumn := []int{value}
+
+	_, err := CheckedSimilarityScore(firstColumn, secondColumn, ReturnOverflowError)
+	if !errors.Is(err, ErrOverflow) {
+		t.Errorf("Expected ErrOverflow, but got %v", err)
+	}
+
+	expected := new(big.Int).Mul(big.NewInt(int64(value)), big.NewInt(2))
+	score, err := CheckedSimilarityScore(firstColumn, secondColumn, PromoteToBigInt)
+	if err != nil || score.Cmp(expected) != 0 {
+		t.Errorf("Expected %v, but got %v (%v)", expected, score, err)
+	}
+}
+
+func TestCheckedMultiply(t *testing.T) {
+	testValues := func(a int, b int, expectedOk bool) {
+		product, ok := checkedMultiply(a, b)
+		if ok != expectedOk {
+			t.Errorf("Expected checkedMultiply(%d, %d) ok to be %t, but got %t", a, b, expectedOk, ok)
+		}
+		if ok && product != a*b {
+			t.Errorf("Expected %d, but got %d", a*b, product)
+		}
+	}
+
+	testValues(3, 4, true)
+	testValues(math.MaxInt64, 1, true)
+	testValues(math.MaxInt64, 2, false)
+	testValues(math.MinInt64, -1, false)
+	testValues(-1, math.MinInt64, false)
+	testValues(math.MaxInt64/2, -2, true)
+}
@@ -0,0 +1,220 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+type HeaderMode int
+
+const (
+	// The first data line is a header when none of its fields is an integer
+	DetectHeader HeaderMode = iota
+	WithHeader
+	WithoutHeader
+)
+
+type ReaderOptions struct {
+	// Field delimiter, detected from the first data line when 0.
+	// ' ' splits on any run of whitespace, like the puzzle input.
+	Delimiter rune
+	// Lines starting with this prefix are skipped, no comments when empty
+	CommentPrefix string
+	Header        HeaderMode
+}
+
+type ParseError struct {
+	Line   int
+	Column int
+	Field  string
+	Err    error
+}
+
+type columnParser struct {
+	options      ReaderOptions
+	columnCount  int
+	seenDataLine bool
+}
+
+type field struct {
+	text   string
+	column int
+}
+
+func DefaultReaderOptions() ReaderOptions {
+	return ReaderOptions{CommentPrefix: "#", Header: DetectHeader}
+}
+
+func LoadColumnsWithOptions(filename string, options ReaderOptions) ([][]int, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return ReadColumns(file, options)
+}
+
+func ReadColumns(input io.Reader, options ReaderOptions) (columns [][]int, err error) {
+	// Reads CSV, TSV or whitespace separated integers, one array per column
+	parser := newColumnParser(options)
+
+	scanner := bufio.NewScanner(input)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		values, err := parser.parseLine(scanner.Text(), lineNumber)
+		if err != nil {
+			return nil, err
+		}
+		if values == nil {
+			continue
+		}
+
+		if columns == nil {
+			columns = make([][]int, len(values))
+		}
+		for i, value := range values {
+			columns[i] = append(columns[i], value)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return columns, nil
+}
+
+func newColumnParser(options ReaderOptions) *columnParser {
+	return &columnParser{options: options}
+}
+
+/************ columnParser methods ************/
+
+func (p *columnParser) parseLine(line string, lineNumber int) ([]int, error) {
+	// Returns nil values for empty lines, comments and the header
+	line = strings.TrimRight(line, "\r")
+	if strings.TrimSpace(line) == "" {
+		return nil, nil
+	}
+
+	if p.options.CommentPrefix != "" && strings.HasPrefix(strings.TrimSpace(line), p.options.CommentPrefix) {
+		return nil, nil
+	}
+
+	if p.options.Delimiter == 0 {
+		p.options.Delimiter = detectDelimiter(line)
+	}
+
+	fields := splitFields(line, p.options.Delimiter)
+
+	isFirstDataLine := !p.seenDataLine
+	p.seenDataLine = true
+
+	if isFirstDataLine && p.options.Header == WithHeader {
+		return nil, nil
+	}
+
+	// A line mixing numbers and text is a corrupt row, not a header
+	if isFirstDataLine && p.options.Header == DetectHeader && !hasIntegerField(fields) {
+		return nil, nil
+	}
+
+	values := make([]int, 0, len(fields))
+	for i, current := range fields {
+		value, err := strconv.Atoi(current.text)
+		if err != nil {
+			return nil, ParseError{Line: lineNumber, Column: current.column, Field: current.text, Err: fmt.Errorf("field %d is not an integer", i+1)}
+		}
+		values = append(values, value)
+	}
+
+	if p.columnCount == 0 {
+		p.columnCount = len(values)
+	}
+
+	if len(values) != p.columnCount {
+		return nil, ParseError{Line: lineNumber, Column: 1, Field: line, Err: fmt.Errorf("expected %d columns, but got %d", p.columnCount, len(values))}
+	}
+
+	return values, nil
+}
+
+func hasIntegerField(fields []field) bool {
+	for _, current := range fields {
+		if _, err := strconv.Atoi(current.text); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+func detectDelimiter(line string) rune {
+	for _, delimiter := range []rune{'\t', ',', ';'} {
+		if strings.ContainsRune(line, delimiter) {
+			return delimiter
+		}
+	}
+	return ' '
+}
+
+func splitFields(line string, delimiter rune) []field {
+	// Splits the line keeping the 1-based column where each trimmed field starts
+	var fields []field
+	runes := []rune(line)
+
+	if delimiter == ' ' {
+		for i := 0; i < len(runes); {
+			if unicode.IsSpace(runes[i]) {
+				i++
+				continue
+			}
+
+			start := i
+			for i < len(runes) && !unicode.IsSpace(runes[i]) {
+				i++
+			}
+			fields = append(fields, field{text: string(runes[start:i]), column: start + 1})
+		}
+
+		return fields
+	}
+
+	start := 0
+	for i := 0; i <= len(runes); i++ {
+		if i < len(runes) && runes[i] != delimiter {
+			continue
+		}
+
+		// Skip the spaces around the value, so the column points at the value itself
+		valueStart := start
+		for valueStart < i && unicode.IsSpace(runes[valueStart]) {
+			valueStart++
+		}
+		valueEnd := i
+		for valueEnd > valueStart && unicode.IsSpace(runes[valueEnd-1]) {
+			valueEnd--
+		}
+
+		fields = append(fields, field{text: string(runes[valueStart:valueEnd]), column: valueStart + 1})
+		start = i + 1
+	}
+
+	return fields
+}
+
+/************ ParseError methods ************/
+
+func (e ParseError) Error() string {
+	return fmt.Sprintf("line %d, column %d: %v (%q)", e.Line, e.Column, e.Err, e.Field)
+}
+
+func (e ParseError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestReadColumns(t *testing.T) {
+	testValues := func(name string, input string, expected [][]int) {
+		columns, err := ReadColumns(strings.NewReader(input), DefaultReaderOptions())
+		if err != nil {
+			t.Fatalf("%s: expected no error, but got %v", name, err)
+		}
+
+		if len(columns) != len(expected) {
+			t.Fatalf("%s: expected %v, but got %v", name, expected, columns)
+		}
+		for i := range expected {
+			for j := range expected[i] {
+				if columns[i][j] != expected[i][j] {
+					t.Errorf("%s: expected columns[%d][%d] to be %d, but got %d", name, i, j, expected[i][j], columns[i][j])
+				}
+			}
+		}
+	}
+
+	expected := [][]int{{3, 4, 2}, {4, 3, 5}}
+
+	testValues("whitespace", "3   4\n4   3\r\n2   5\n", expected)
+	testValues("csv", "3,4\n4,3\n2,5\n", expected)
+	testValues("csv with spaces", "3, 4\n4 ,3\n2 , 5\n", expected)
+	testValues("tsv", "3\t4\n4\t3\n2\t5\n", expected)
+	testValues("semicolon", "3;4\n4;3\n2;5\n", expected)
+	testValues("header and comments", "# exported list\nleft,right\n3,4\n# middle comment\n4,3\n\n2,5\n", expected)
+}
+
+func TestReadColumns_ExplicitOptions(t *testing.T) {
+	// A numeric header is only skipped when asked to
+	options := ReaderOptions{Delimiter: ',', Header: WithHeader}
+	columns, err := ReadColumns(strings.NewReader("1,2\n3,4\n"), options)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+	if len(columns[0]) != 1 || columns[0][0] != 3 {
+		t.Errorf("Expected the first line to be skipped, but got %v", columns)
+	}
+
+	// Without header, a text first line is an error
+	options = ReaderOptions{Header: WithoutHeader}
+	_, err = ReadColumns(strings.NewReader("left right\n3 4\n"), options)
+	if err == nil {
+		t.Errorf("Expected an error for a header when WithoutHeader is used")
+	}
+
+	// Without comment prefix, # lines are data
+	options = ReaderOptions{Header: WithoutHeader}
+	_, err = ReadColumns(strings.NewReader("# comment\n3 4\n"), options)
+	if err == nil {
+		t.Errorf("Expected an error for a comment when there is no comment prefix")
+	}
+}
+
+func TestReadColumns_ParseError(t *testing.T) {
+	testValues := func(input string, expectedLine int, expectedColumn int, expectedField string) {
+		_, err := ReadColumns(strings.NewReader(input), DefaultReaderOptions())
+
+		var parseError ParseError
+		if !errors.As(err, &parseError) {
+			t.Fatalf("Expected a ParseError, but got %v", err)
+		}
+		if parseError.Line != expectedLine || parseError.Column != expectedColumn || parseError.Field != expectedField {
+			t.Errorf("Expected line %d, column %d, field %q, but got %v", expectedLine, expectedColumn, expectedField, parseError)
+		}
+	}
+
+	testValues("3   4\n4   x3\n", 2, 5, "x3")
+	testValues("left,right\n3,4\n4, 3.5\n", 3, 4, "3.5")
+	testValues("3\t4\n4\t3\t9\n", 2, 1, "4\t3\t9")
+	// A corrupt first row is not mistaken for a header
+	testValues("3,x\n4,3\n", 1, 3, "x")
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+type ValueCounts struct {
+	Value        int
+	FirstCount   int
+	SecondCount  int
+	Contribution int
+}
+
+type ReconciliationSummary struct {
+	OnlyFirstValues       int
+	OnlyFirstOccurrences  int
+	OnlySecondValues      int
+	OnlySecondOccurrences int
+	DifferentCountValues  int
+	SameCountValues       int
+	// Sum of the contributions of the values found in both lists, equal to CalculateSimilarityScore
+	SimilarityScore int
+}
+
+type ReconciliationReport struct {
+	OnlyFirst      []ValueCounts
+	OnlySecond     []ValueCounts
+	DifferentCount []ValueCounts
+	SameCount      []ValueCounts
+	Summary        ReconciliationSummary
+}
+
+func NewReconciliationReport(firstColumn []int, secondColumn []int) ReconciliationReport {
+	// Every distinct value lands in exactly one category, listed lowest to highest
+	firstIndex := NewFrequencyIndex(firstColumn)
+	secondIndex := NewFrequencyIndex(secondColumn)
+
+	var values []int
+	for value := range firstIndex.counts {
+		values = append(values, value)
+	}
+	for value := range secondIndex.counts {
+		if firstIndex.Occurrences(value) == 0 {
+			values = append(values, value)
+		}
+	}
+	slices.Sort(values)
+
+	var report ReconciliationReport
+	for _, value := range values {
+		counts := ValueCounts{
+			Value:       value,
+			FirstCount:  firstIndex.Occurrences(value),
+			SecondCount: secondIndex.Occurrences(value),
+		}
+		// Same as CalculateSimilarityScore, each left occurrence is worth value * right occurrences
+		counts.Contribution = value * counts.FirstCount * counts.SecondCount
+
+		switch {
+		case counts.SecondCount == 0:
+			report.OnlyFirst = append(report.OnlyFirst, counts)
+			report.Summary.OnlyFirstValues++
+			report.Summary.OnlyFirstOccurrences += counts.FirstCount
+		case counts.FirstCount == 0:
+			report.OnlySecond = append(report.OnlySecond, counts)
+			report.Summary.OnlySecondValues++
+			report.Summary.OnlySecondOccurrences += counts.SecondCount
+		case counts.FirstCount != counts.SecondCount:
+			report.DifferentCount = append(report.DifferentCount, counts)
+			report.Summary.DifferentCountValues++
+		default:
+			report.SameCount = append(report.SameCount, counts)
+			report.Summary.SameCountValues++
+		}
+
+		report.Summary.SimilarityScore += counts.Contribution
+	}
+
+	return report
+}
+
+/************ ReconciliationReport methods ************/
+
+func (r ReconciliationReport) Text() string {
+	var builder strings.Builder
+
+	writeCategory := func(title string, values []ValueCounts) {
+		fmt.Fprintf(&builder, "%s (%d)\n", title, len(values))
+		for _, counts := range values {
+			fmt.Fprintf(&builder, "  %d: %d left, %d right\n", counts.Value, counts.FirstCount, counts.SecondCount)
+		}
+	}
+
+	writeCategory("Only in left list", r.OnlyFirst)
+	writeCategory("Only in right list", r.OnlySecond)
+	writeCategory("In both lists, different counts", r.DifferentCount)
+	writeCategory("In both lists, same counts", r.SameCount)
+
+	fmt.Fprintf(&builder, "Similarity score: %d\n", r.Summary.SimilarityScore)
+
+	return builder.String()
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestNewReconciliationReport(t *testing.T) {
+	// Left: 3 4 2 1 3 3, right: 4 3 5 3 9 3
+	report := NewReconciliationReport(GetFirstColumn(), GetSecondColumn())
+
+	testValues := func(name string, values []ValueCounts, expected []ValueCounts) {
+		if len(values) != len(expected) {
+			t.Errorf("Expected %s to be %v, but got %v", name, expected, values)
+			return
+		}
+		for i := range expected {
+			if values[i] != expected[i] {
+				t.Errorf("Expected %s[%d] to be %v, but got %v", name, i, expected[i], values[i])
+			}
+		}
+	}
+
+	testValues("OnlyFirst", report.OnlyFirst, []ValueCounts{{1, 1, 0, 0}, {2, 1, 0, 0}})
+	testValues("OnlySecond", report.OnlySecond, []ValueCounts{{5, 0, 1, 0}, {9, 0, 1, 0}})
+	testValues("DifferentCount", report.DifferentCount, nil)
+	testValues("SameCount", report.SameCount, []ValueCounts{{3, 3, 3, 27}, {4, 1, 1, 4}})
+
+	expectedSummary := ReconciliationSummary{
+		OnlyFirstValues:       2,
+		OnlyFirstOccurrences:  2,
+		OnlySecondValues:      2,
+		OnlySecondOccurrences: 2,
+		DifferentCountValues:  0,
+		SameCountValues:       2,
+		SimilarityScore:       31,
+	}
+	if report.Summary != expectedSummary {
+		t.Errorf("Expected summary %v, but got %v", expectedSummary, report.Summary)
+	}
+}
+
+func TestNewReconciliationReport_MatchesSimilarityScore(t *testing.T) {
+	random := rand.New(rand.NewSource(8))
+
+	for round := 0; round < 50; round++ {
+		firstColumn := make([]int, random.Intn(30))
+		secondColumn := make([]int, random.Intn(30))
+		for i := range firstColumn {
+			firstColumn[i] = random.Intn(15)
+		}
+		for i := range secondColumn {
+			secondColumn[i] = random.Intn(15)
+		}
+
+		report := NewReconciliationReport(firstColumn, secondColumn)
+		if report.Summary.SimilarityScore != CalculateSimilarityScore(firstColumn, secondColumn) {
+			t.Errorf("Expected %d, but got %d", CalculateSimilarityScore(firstColumn, secondColumn), report.Summary.SimilarityScore)
+		}
+
+		// Every occurrence is accounted for
+		occurrences := report.Summary.OnlyFirstOccurrences
+		for _, counts := range append(report.DifferentCount, report.SameCount...) {
+			occurrences += counts.FirstCount
+		}
+		if occurrences != len(firstColumn) {
+			t.Errorf("Expected %d left occurrences, but got %d", len(firstColumn), occurrences)
+		}
+	}
+}
+
+func TestReconciliationReport_Text(t *testing.T) {
+	report := NewReconciliationReport([]int{1, 2, 2}, []int{2, 3})
+
+	expected := "" +
+		"Only in left list (1)\n" +
+		"  1: 1 left, 0 right\n" +
+		"Only in right list (1)\n" +
+		"  3: 0 left, 1 right\n" +
+		"In both lists, different counts (1)\n" +
+		"  2: 2 left, 1 right\n" +
+		"In both lists, same counts (0)\n" +
+		"Similarity score: 4\n"
+
+	if report.Text() != expected {
+		t.Errorf("Expected\n%s\nbut got\n%s", expected, report.Text())
+	}
+}
+
+func TestNewReconciliationReport_DifferentLengths(t *testing.T) {
+	// Lists that can't be paired can still be reconciled
+	report := NewReconciliationReport([]int{1, 2, 2, 7}, []int{2, 5})
+
+	if len(report.OnlyFirst) != 2 || len(report.OnlySecond) != 1 || len(report.DifferentCount) != 1 {
+		t.Errorf("Expected 2 values only in the first list, 1 only in the second and 1 with different counts, but got %+v", report)
+	}
+	if report.Summary.SimilarityScore != 4 {
+		t.Errorf("Expected a similarity score of 4, but got %d", report.Summary.SimilarityScore)
+	}
+}
@@ -0,0 +1,263 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"sort"
+)
+
+type Side int
+
+const (
+	FirstSide Side = iota
+	SecondSide
+)
+
+type TrackerTotals struct {
+	// TotalDistance is only meaningful when both lists have the same length, it is 0 otherwise
+	TotalDistance   int
+	SimilarityScore int
+	FirstLength     int
+	SecondLength    int
+}
+
+type SimilarityTracker struct {
+	blocks          []*trackerBlock
+	similarityScore int
+	firstLength     int
+	secondLength    int
+}
+
+type trackedValue struct {
+	value  int
+	first  int
+	second int
+}
+
+type trackerBlock struct {
+	values []trackedValue
+	// Sum of (first - second) counts of the whole block
+	total int
+	// Running (first - second) counts inside the block, sorted, with the matching gap to the next value
+	// and prefix sums of gap and gap * running count, to sum |offset + running| * gap with a binary search.
+	sortedRunning      []int
+	weightPrefix       []int
+	weightedPrefix     []int
+	totalWeight        int
+	totalWeightedCount int
+}
+
+// Blocks are split once they hold twice this many distinct values
+const trackerBlockSize = 128
+
+func NewSimilarityTracker(firstColumn []int, secondColumn []int) *SimilarityTracker {
+	tracker := &SimilarityTracker{}
+	for _, value := range firstColumn {
+		tracker.Insert(FirstSide, value)
+	}
+	for _, value := range secondColumn {
+		tracker.Insert(SecondSide, value)
+	}
+	return tracker
+}
+
+/************ SimilarityTracker methods ************/
+
+func (t *SimilarityTracker) Insert(side Side, value int) TrackerTotals {
+	blockIndex, position, found := t.locate(value)
+	if blockIndex == len(t.blocks) {
+		t.blocks = append(t.blocks, &trackerBlock{})
+	}
+
+	block := t.blocks[blockIndex]
+	if !found {
+		block.values = slices.Insert(block.values, position, trackedValue{value: value})
+	}
+
+	tracked := &block.values[position]
+	if side == FirstSide {
+		t.similarityScore += value * tracked.second
+		tracked.first++
+		t.firstLength++
+	} else {
+		t.similarityScore += value * tracked.first
+		tracked.second++
+		t.secondLength++
+	}
+
+	t.update(blockIndex)
+	return t.Totals()
+}
+
+func (t *SimilarityTracker) Remove(side Side, value int) (TrackerTotals, error) {
+	blockIndex, position, found := t.locate(value)
+	if !found || t.blocks[blockIndex].values[position].count(side) == 0 {
+		return t.Totals(), fmt.Errorf("value %d is not in the list", value)
+	}
+
+	block := t.blocks[blockIndex]
+	tracked := &block.values[position]
+	if side == FirstSide {
+		tracked.first--
+		t.similarityScore -= value * tracked.second
+		t.firstLength--
+	} else {
+		tracked.second--
+		t.similarityScore -= value * tracked.first
+		t.secondLength--
+	}
+
+	if tracked.first == 0 && tracked.second == 0 {
+		block.values = slices.Delete(block.values, position, position+1)
+	}
+
+	t.update(blockIndex)
+	return t.Totals(), nil
+}
+
+func (t *SimilarityTracker) Totals() TrackerTotals {
+	totals := TrackerTotals{
+		SimilarityScore: t.similarityScore,
+		FirstLength:     t.firstLength,
+		SecondLength:    t.secondLength,
+	}
+
+	if t.firstLength == t.secondLength {
+		totals.TotalDistance = t.totalDistance()
+	}
+
+	return totals
+}
+
+func (t *SimilarityTracker) totalDistance() int {
+	// Pairing both sorted lists is the same as summing, over every gap between two consecutive values,
+	// the gap times how many more values one list has than the other below that gap.
+	distance := 0
+	offset := 0
+	for _, block := range t.blocks {
+		distance += block.distance(offset)
+		offset += block.total
+	}
+	return distance
+}
+
+func (t *SimilarityTracker) locate(value int) (blockIndex int, position int, found bool) {
+	// Finds the block holding the value, or where it should be inserted
+	blockIndex = sort.Search(len(t.blocks), func(i int) bool {
+		values := t.blocks[i].values
+		return values[len(values)-1].value >= value
+	})
+
+	// Past the last value, it goes at the end of the last block
+	if blockIndex == len(t.blocks) && blockIndex > 0 {
+		blockIndex--
+	}
+	if blockIndex == len(t.blocks) {
+		return blockIndex, 0, false
+	}
+
+	values := t.blocks[blockIndex].values
+	position = sort.Search(len(values), func(i int) bool {
+		return values[i].value >= value
+	})
+
+	return blockIndex, position, position < len(values) && values[position].value == value
+}
+
+func (t *SimilarityTracker) update(blockIndex int) {
+	// Keeps blocks non empty and bounded, then rebuilds the block and its neighbours,
+	// since the gap of the last value of a block depends on the first value of the next one.
+	block := t.blocks[blockIndex]
+
+	if len(block.values) == 0 {
+		t.blocks = slices.Delete(t.blocks, blockIndex, blockIndex+1)
+		t.rebuild(blockIndex - 1)
+		return
+	}
+
+	if len(block.values) > 2*trackerBlockSize {
+		half := len(block.values) / 2
+		next := &trackerBlock{values: slices.Clone(block.values[half:])}
+		block.values = slices.Clip(block.values[:half])
+		t.blocks = slices.Insert(t.blocks, blockIndex+1, next)
+		t.rebuild(blockIndex + 1)
+	}
+
+	t.rebuild(blockIndex)
+	t.rebuild(blockIndex - 1)
+}
+
+func (t *SimilarityTracker) rebuild(blockIndex int) {
+	if blockIndex < 0 || blockIndex >= len(t.blocks) {
+		return
+	}
+
+	nextValue, hasNext := 0, false
+	if blockIndex+1 < len(t.blocks) {
+		nextValue, hasNext = t.blocks[blockIndex+1].values[0].value, true
+	}
+
+	t.blocks[blockIndex].rebuild(nextValue, hasNext)
+}
+
+/************ trackerBlock methods ************/
+
+func (b *trackerBlock) rebuild(nextValue int, hasNext bool) {
+	type runningGap struct {
+		running int
+		gap     int
+	}
+
+	entries := make([]runningGap, len(b.values))
+	running := 0
+	for i, tracked := range b.values {
+		running += tracked.first - tracked.second
+
+		gap := 0
+		if i+1 < len(b.values) {
+			gap = b.values[i+1].value - tracked.value
+		} else if hasNext {
+			gap = nextValue - tracked.value
+		}
+
+		entries[i] = runningGap{running: running, gap: gap}
+	}
+	b.total = running
+
+	slices.SortFunc(entries, func(x, y runningGap) int {
+		return cmp.Compare(x.running, y.running)
+	})
+
+	b.sortedRunning = make([]int, len(entries))
+	b.weightPrefix = make([]int, len(entries)+1)
+	b.weightedPrefix = make([]int, len(entries)+1)
+	for i, entry := range entries {
+		b.sortedRunning[i] = entry.running
+		b.weightPrefix[i+1] = b.weightPrefix[i] + entry.gap
+		b.weightedPrefix[i+1] = b.weightedPrefix[i] + entry.gap*entry.running
+	}
+	b.totalWeight = b.weightPrefix[len(entries)]
+	b.totalWeightedCount = b.weightedPrefix[len(entries)]
+}
+
+func (b *trackerBlock) distance(offset int) int {
+	// Sum of |offset + running| * gap, split where offset + running changes sign
+	split := sort.SearchInts(b.sortedRunning, -offset)
+
+	belowWeight := b.weightPrefix[split]
+	belowWeighted := b.weightedPrefix[split]
+	aboveWeight := b.totalWeight - belowWeight
+	aboveWeighted := b.totalWeightedCount - belowWeighted
+
+	return (offset*aboveWeight + aboveWeighted) - (offset*belowWeight + belowWeighted)
+}
+
+/************ trackedValue methods ************/
+
+func (v trackedValue) count(side Side) int {
+	if side == FirstSide {
+		return v.first
+	}
+	return v.second
+}
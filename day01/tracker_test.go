@@ -0,0 +1,102 @@
+package main
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestSimilarityTracker(t *testing.T) {
+	// it should return 11 and 31 for the example lists
+	tracker := NewSimilarityTracker(GetFirstColumn(), GetSecondColumn())
+
+	totals := tracker.Totals()
+	if totals.TotalDistance != 11 || totals.SimilarityScore != 31 {
+		t.Errorf("Expected 11 and 31, but got %d and %d", totals.TotalDistance, totals.SimilarityScore)
+	}
+
+	// Adding a 4 on both sides: 3 4 2 1 3 3 4 and 4 3 5 3 9 3 4
+	tracker.Insert(FirstSide, 4)
+	totals = tracker.Insert(SecondSide, 4)
+	expectedDistance := FirstPart([]int{3, 4, 2, 1, 3, 3, 4}, []int{4, 3, 5, 3, 9, 3, 4})
+	expectedScore := SecondPart([]int{3, 4, 2, 1, 3, 3, 4}, []int{4, 3, 5, 3, 9, 3, 4})
+	if totals.TotalDistance != expectedDistance || totals.SimilarityScore != expectedScore {
+		t.Errorf("Expected %d and %d, but got %d and %d", expectedDistance, expectedScore, totals.TotalDistance, totals.SimilarityScore)
+	}
+}
+
+func TestSimilarityTracker_DifferentLengths(t *testing.T) {
+	tracker := NewSimilarityTracker(GetFirstColumn(), GetSecondColumn())
+
+	// The distance is 0 until both lists have the same length again
+	totals := tracker.Insert(FirstSide, 9)
+	if totals.TotalDistance != 0 || totals.FirstLength != 7 || totals.SecondLength != 6 {
+		t.Errorf("Expected distance 0 with lengths 7 and 6, but got %v", totals)
+	}
+
+	// 9 appears once on the other side
+	if totals.SimilarityScore != 31+9 {
+		t.Errorf("Expected score %d, but got %d", 31+9, totals.SimilarityScore)
+	}
+}
+
+func TestSimilarityTracker_RemoveMissingValue(t *testing.T) {
+	tracker := NewSimilarityTracker(GetFirstColumn(), GetSecondColumn())
+
+	// 9 is only in the second list
+	if _, err := tracker.Remove(FirstSide, 9); err == nil {
+		t.Errorf("Expected an error when removing a missing value")
+	}
+	if _, err := tracker.Remove(FirstSide, 100); err == nil {
+		t.Errorf("Expected an error when removing a missing value")
+	}
+
+	if tracker.Totals().SimilarityScore != 31 {
+		t.Errorf("Expected the score to be unchanged, but got %d", tracker.Totals().SimilarityScore)
+	}
+}
+
+func TestSimilarityTracker_MatchesRecomputation(t *testing.T) {
+	// Random inserts and removals, checked against FirstPart and SecondPart after each change.
+	// Enough distinct values to split blocks.
+	random := rand.New(rand.NewSource(7))
+	tracker := NewSimilarityTracker(nil, nil)
+	var firstColumn, secondColumn []int
+
+	for step := 0; step < 3000; step++ {
+		side := Side(random.Intn(2))
+		column := &firstColumn
+		if side == SecondSide {
+			column = &secondColumn
+		}
+
+		var totals TrackerTotals
+		if len(*column) > 0 && random.Intn(3) == 0 {
+			index := random.Intn(len(*column))
+			value := (*column)[index]
+			*column = slices.Delete(*column, index, index+1)
+
+			var err error
+			totals, err = tracker.Remove(side, value)
+			if err != nil {
+				t.Fatalf("Expected no error, but got %v", err)
+			}
+		} else {
+			value := random.Intn(1000) - 200
+			*column = append(*column, value)
+			totals = tracker.Insert(side, value)
+		}
+
+		expectedScore := SecondPart(firstColumn, secondColumn)
+		if totals.SimilarityScore != expectedScore {
+			t.Fatalf("Step %d: expected score %d, but got %d", step, expectedScore, totals.SimilarityScore)
+		}
+
+		if len(firstColumn) == len(secondColumn) {
+			expectedDistance := FirstPart(firstColumn, secondColumn)
+			if totals.TotalDistance != expectedDistance {
+				t.Fatalf("Step %d: expected distance %d, but got %d", step, expectedDistance, totals.TotalDistance)
+			}
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package main
+
+func CountSafeReportsWithRemovals(reports [][]int, maxRemovals int, policy ...SafetyPolicy) int {
+	// Same as CountSafeReportsWithDampener, but the dampener can remove up to maxRemovals levels
+	selectedPolicy := selectPolicy(policy)
+
+	count := 0
+	for _, report := range reports {
+		if _, ok := CanBeMadeSafe(report, maxRemovals, selectedPolicy); ok {
+			count++
+		}
+	}
+	return count
+}
+
+func CanBeMadeSafe(report []int, maxRemovals int, policy ...SafetyPolicy) (removed []int, ok bool) {
+	// Returns the fewest indexes to remove to make the report safe, if there are at most maxRemovals of them.
+	// The report is never copied, kept levels are tracked by index.
+	selectedPolicy := selectPolicy(policy)
+
+	trends := []Trend{Increasing, Decreasing}
+	if selectedPolicy.Trend != EitherTrend {
+		trends = []Trend{selectedPolicy.Trend}
+	}
+
+	for _, trend := range trends {
+		candidate, found := removalsForTrend(report, maxRemovals, selectedPolicy, trend)
+		if found && (!ok || len(candidate) < len(removed)) {
+			removed, ok = candidate, true
+		}
+	}
+
+	return removed, ok
+}
+
+func removalsForTrend(report []int, maxRemovals int, policy SafetyPolicy, trend Trend) ([]int, bool) {
+	// reachable[i][r] is true when level i can be the last kept level, with r levels removed before it.
+	// The previous kept level is at most maxRemovals + 1 positions back, so it is O(n * k^2) with no copy.
+	// It is not O(n * k): whether a previous kept level j fits depends on report[j] against report[i],
+	// so no single "best j" can be carried forward per removal count, the r + 1 candidates are each checked.
+	// For the dampener k is 1, and k stays small next to n, so the extra factor of k is cheap.
+	length := len(report)
+	if length == 0 {
+		return nil, policy.minimumLevels() == 0
+	}
+
+	if maxRemovals < 0 {
+		maxRemovals = 0
+	}
+
+	const start = -1
+	reachable := make([][]bool, length)
+	previous := make([][]int, length)
+
+	for i := 0; i < length; i++ {
+		reachable[i] = make([]bool, maxRemovals+1)
+		previous[i] = make([]int, maxRemovals+1)
+
+		// Every level before i is removed, i is the first kept level
+		if i <= maxRemovals {
+			reachable[i][i] = true
+			previous[i][i] = start
+		}
+
+		for r := 0; r <= maxRemovals; r++ {
+			if reachable[i][r] {
+				continue
+			}
+
+			// j is the previous kept level, the levels between j and i are removed
+			for gap := 0; gap <= r && i-1-gap >= 0; gap++ {
+				j := i - 1 - gap
+				if reachable[j][r-gap] && policy.isStepAllowedForTrend(report[j], report[i], trend) {
+					reachable[i][r] = true
+					previous[i][r] = j
+					break
+				}
+			}
+		}
+	}
+
+	// Pick the last kept level that needs the fewest removals overall,
+	// keeping enough levels for the policy
+	bestLast, bestRemovals, bestTotal := -1, 0, maxRemovals+1
+	for i := 0; i < length; i++ {
+		after := length - 1 - i
+		for r := 0; r <= maxRemovals; r++ {
+			keptLevels := i + 1 - r
+			if reachable[i][r] && r+after < bestTotal && keptLevels >= policy.minimumLevels() {
+				bestLast, bestRemovals, bestTotal = i, r, r+after
+			}
+		}
+	}
+
+	if bestLast == -1 {
+		return nil, false
+	}
+
+	// Walk back through the kept levels, collecting the gaps between them
+	kept := make([]bool, length)
+	for i, r := bestLast, bestRemovals; i != start; {
+		kept[i] = true
+		j := previous[i][r]
+		r -= i - 1 - j
+		i = j
+	}
+
+	removed := []int{}
+	for i := 0; i < length; i++ {
+		if !kept[i] {
+			removed = append(removed, i)
+		}
+	}
+
+	return removed, true
+}
+
+/************ SafetyPolicy methods ************/
+
+func (p SafetyPolicy) isStepAllowedForTrend(current int, next int, trend Trend) bool {
+	distance := CalculateDistance(current, next)
+	if p.stepViolation(distance) != NoViolation {
+		return false
+	}
+
+	return distance == 0 || trendOf(distance) == trend
+}
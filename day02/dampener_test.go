@@ -0,0 +1,109 @@
+package main
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func bruteForceMinimumRemovals(report []int, maxRemovals int, policy SafetyPolicy) (int, bool) {
+	// Tries every subset of removed levels, smallest subsets first
+	for removals := 0; removals <= maxRemovals && removals <= len(report); removals++ {
+		var try func(start int, left int, current []int) bool
+		try = func(start int, left int, current []int) bool {
+			if left == 0 {
+				return IsReportSafe(current, policy)
+			}
+			for i := start; i < len(current); i++ {
+				if try(i, left-1, RemoveIndexFromArray(current, i)) {
+					return true
+				}
+			}
+			return false
+		}
+
+		if try(0, removals, report) {
+			return removals, true
+		}
+	}
+	return 0, false
+}
+
+func removeIndexes(report []int, indexes []int) []int {
+	var kept []int
+	for i, level := range report {
+		if !slices.Contains(indexes, i) {
+			kept = append(kept, level)
+		}
+	}
+	return kept
+}
+
+func TestCanBeMadeSafe(t *testing.T) {
+	testValues := func(report []int, maxRemovals int, expectedRemoved []int, expectedOk bool) {
+		removed, ok := CanBeMadeSafe(report, maxRemovals)
+		if ok != expectedOk || !slices.Equal(removed, expectedRemoved) {
+			t.Errorf("Expected %v (%t), but got %v (%t). Report: %v", expectedRemoved, expectedOk, removed, ok, report)
+		}
+	}
+
+	testValues([]int{7, 6, 4, 2, 1}, 1, []int{}, true)
+	testValues([]int{1, 2, 7, 8, 9}, 1, nil, false)
+	testValues([]int{9, 7, 6, 2, 1}, 1, nil, false)
+	testValues([]int{1, 3, 2, 4, 5}, 1, []int{1}, true)
+	testValues([]int{8, 6, 4, 4, 1}, 1, []int{2}, true)
+
+	// Two removals make 1 2 7 8 9 safe by dropping 1 and 2
+	testValues([]int{1, 2, 7, 8, 9}, 2, []int{0, 1}, true)
+	testValues([]int{1, 2, 7, 3, 8, 4}, 2, []int{2, 4}, true)
+}
+
+func TestCountSafeReportsWithRemovals_ReproducesSecondPart(t *testing.T) {
+	// k = 1 is the Problem Dampener
+	reports := GetTestReports()
+	if CountSafeReportsWithRemovals(reports, 1) != 4 {
+		t.Errorf("Expected 4, but got %d", CountSafeReportsWithRemovals(reports, 1))
+	}
+	if CountSafeReportsWithRemovals(reports, 0) != CountSafeReports(reports) {
+		t.Errorf("Expected k = 0 to match CountSafeReports")
+	}
+}
+
+func TestCanBeMadeSafe_MatchesBruteForce(t *testing.T) {
+	random := rand.New(rand.NewSource(13))
+	policies := []SafetyPolicy{
+		DefaultSafetyPolicy(),
+		{MinStep: 1, MaxStep: 3, Trend: Increasing},
+		{MinStep: 2, MaxStep: 4, Trend: EitherTrend, AllowFlat: true},
+		{MinStep: 1, MaxStep: 3, Trend: EitherTrend, ShortReports: ShortReportsAreUnsafe},
+	}
+
+	for round := 0; round < 500; round++ {
+		report := make([]int, random.Intn(8))
+		level := random.Intn(20)
+		for i := range report {
+			level += random.Intn(9) - 4
+			report[i] = level
+		}
+
+		for _, policy := range policies {
+			for maxRemovals := 0; maxRemovals <= 3; maxRemovals++ {
+				expectedRemovals, expectedOk := bruteForceMinimumRemovals(report, maxRemovals, policy)
+				removed, ok := CanBeMadeSafe(report, maxRemovals, policy)
+
+				if ok != expectedOk {
+					t.Fatalf("Expected %t, but got %t. Report: %v, k: %d, policy: %+v", expectedOk, ok, report, maxRemovals, policy)
+				}
+				if !ok {
+					continue
+				}
+				if len(removed) != expectedRemovals {
+					t.Errorf("Expected %d removals, but got %v. Report: %v", expectedRemovals, removed, report)
+				}
+				if !IsReportSafe(removeIndexes(report, removed), policy) {
+					t.Errorf("Expected the report without %v to be safe. Report: %v", removed, report)
+				}
+			}
+		}
+	}
+}
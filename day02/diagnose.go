@@ -0,0 +1,176 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type ViolationReason int
+
+const (
+	NoViolation ViolationReason = iota
+	StepTooSmall
+	StepTooLarge
+	ZeroStep
+	// The step goes the other way than the previous ones
+	TrendReversal
+	// The step goes the way the policy doesn't allow
+	WrongTrend
+	// Fewer than 2 levels, with a policy that doesn't accept it
+	TooFewLevels
+)
+
+type Violation struct {
+	// The two adjacent levels that are not safe
+	Index     int
+	NextIndex int
+	Reason    ViolationReason
+	// Level at NextIndex minus level at Index
+	Delta int
+}
+
+type ReportDiagnosis struct {
+	ReportIndex int
+	Report      []int
+	Violation   Violation
+}
+
+func DiagnoseReport(report []int, policy ...SafetyPolicy) (Violation, bool) {
+	// Returns the first pair of levels that is not safe, false when the report is safe
+	selectedPolicy := selectPolicy(policy)
+
+	length := len(report)
+	if length < selectedPolicy.minimumLevels() {
+		return Violation{Reason: TooFewLevels}, true
+	}
+
+	// The trend is set by the first step that is not flat
+	var trend Trend
+	hasTrend := false
+
+	for i := 0; i < length-1; i++ {
+		distance := CalculateDistance(report[i], report[i+1])
+		violation := Violation{Index: i, NextIndex: i + 1, Delta: -distance}
+
+		if reason := selectedPolicy.stepViolation(distance); reason != NoViolation {
+			violation.Reason = reason
+			return violation, true
+		}
+
+		if distance == 0 {
+			continue
+		}
+
+		currentTrend := trendOf(distance)
+		if !selectedPolicy.isTrendAllowed(currentTrend) {
+			violation.Reason = WrongTrend
+			return violation, true
+		}
+
+		if hasTrend && trend != currentTrend {
+			violation.Reason = TrendReversal
+			return violation, true
+		}
+
+		trend = currentTrend
+		hasTrend = true
+	}
+
+	return Violation{}, false
+}
+
+func CheckReport(report []int, policy ...SafetyPolicy) (bool, error) {
+	// Same as IsReportSafe, but a short report is an error with ShortReportsAreErrors
+	selectedPolicy := selectPolicy(policy)
+	if selectedPolicy.ShortReports == ShortReportsAreErrors && len(report) < 2 {
+		return false, ErrShortReport
+	}
+
+	_, found := DiagnoseReport(report, selectedPolicy)
+	return !found, nil
+}
+
+func ValidateReports(reports [][]int, policy ...SafetyPolicy) error {
+	// Lists every short report when the policy makes them an error
+	selectedPolicy := selectPolicy(policy)
+	if selectedPolicy.ShortReports != ShortReportsAreErrors {
+		return nil
+	}
+
+	var indexes []int
+	for i, report := range reports {
+		if len(report) < 2 {
+			indexes = append(indexes, i)
+		}
+	}
+
+	if len(indexes) > 0 {
+		return ShortReportsError{Indexes: indexes}
+	}
+	return nil
+}
+
+func DiagnoseReports(reports [][]int, policy ...SafetyPolicy) (diagnoses []ReportDiagnosis) {
+	// One diagnosis per unsafe report, in the order of the reports
+	for i, report := range reports {
+		violation, found := DiagnoseReport(report, policy...)
+		if !found {
+			continue
+		}
+
+		diagnoses = append(diagnoses, ReportDiagnosis{ReportIndex: i, Report: report, Violation: violation})
+	}
+
+	return diagnoses
+}
+
+/************ ViolationReason methods ************/
+
+func (r ViolationReason) String() string {
+	switch r {
+	case NoViolation:
+		return "safe"
+	case StepTooSmall:
+		return "step too small"
+	case StepTooLarge:
+		return "step too large"
+	case ZeroStep:
+		return "zero step"
+	case TrendReversal:
+		return "trend reversal"
+	case WrongTrend:
+		return "wrong trend"
+	case TooFewLevels:
+		return "too few levels"
+	default:
+		return "unknown reason " + strconv.Itoa(int(r))
+	}
+}
+
+/************ ReportDiagnosis methods ************/
+
+func (d ReportDiagnosis) String() string {
+	// e.g. "report 5 [8 6 4 4 1]: zero step between indexes 2 and 3 (4 -> 4, delta 0)"
+	levels := make([]string, len(d.Report))
+	for i, level := range d.Report {
+		levels[i] = strconv.Itoa(level)
+	}
+
+	violation := d.Violation
+	if violation.Reason == TooFewLevels {
+		return fmt.Sprintf("report %d [%s]: %s", d.ReportIndex+1, strings.Join(levels, " "), violation.Reason)
+	}
+
+	return fmt.Sprintf(
+		"report %d [%s]: %s between indexes %d and %d (%d -> %d, delta %d)",
+		d.ReportIndex+1,
+		strings.Join(levels, " "),
+		violation.Reason,
+		violation.Index,
+		violation.NextIndex,
+		d.Report[violation.Index],
+		d.Report[violation.NextIndex],
+		violation.Delta,
+	)
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestDiagnoseReport(t *testing.T) {
+	testValues := func(report []int, policy SafetyPolicy, expected Violation, expectedFound bool) {
+		violation, found := DiagnoseReport(report, policy)
+		if found != expectedFound || violation != expected {
+			t.Errorf("Expected %+v (%t), but got %+v (%t). Report: %v", expected, expectedFound, violation, found, report)
+		}
+	}
+
+	policy := DefaultSafetyPolicy()
+
+	testValues([]int{7, 6, 4, 2, 1}, policy, Violation{}, false)
+	testValues([]int{1, 2, 7, 8, 9}, policy, Violation{Index: 1, NextIndex: 2, Reason: StepTooLarge, Delta: 5}, true)
+	testValues([]int{9, 7, 6, 2, 1}, policy, Violation{Index: 2, NextIndex: 3, Reason: StepTooLarge, Delta: -4}, true)
+	testValues([]int{1, 3, 2, 4, 5}, policy, Violation{Index: 1, NextIndex: 2, Reason: TrendReversal, Delta: -1}, true)
+	testValues([]int{8, 6, 4, 4, 1}, policy, Violation{Index: 2, NextIndex: 3, Reason: ZeroStep, Delta: 0}, true)
+
+	// Policy specific reasons
+	policy.MinStep = 2
+	testValues([]int{1, 3, 4}, policy, Violation{Index: 1, NextIndex: 2, Reason: StepTooSmall, Delta: 1}, true)
+
+	policy = DefaultSafetyPolicy()
+	policy.Trend = Increasing
+	testValues([]int{3, 2, 1}, policy, Violation{Index: 0, NextIndex: 1, Reason: WrongTrend, Delta: -1}, true)
+}
+
+func TestDiagnoseReports(t *testing.T) {
+	// One diagnosis for each of the 4 unsafe test reports
+	diagnoses := DiagnoseReports(GetTestReports())
+
+	expectedIndexes := []int{1, 2, 3, 4}
+	if len(diagnoses) != len(expectedIndexes) {
+		t.Fatalf("Expected %d diagnoses, but got %d", len(expectedIndexes), len(diagnoses))
+	}
+
+	for i, expected := range expectedIndexes {
+		if diagnoses[i].ReportIndex != expected {
+			t.Errorf("Expected diagnoses[%d].ReportIndex to be %d, but got %d", i, expected, diagnoses[i].ReportIndex)
+		}
+	}
+
+	// The count of safe reports is the count of reports without a diagnosis
+	if len(GetTestReports())-len(diagnoses) != CountSafeReports(GetTestReports()) {
+		t.Errorf("Expected diagnoses to match CountSafeReports")
+	}
+}
+
+func TestReportDiagnosis_String(t *testing.T) {
+	diagnosis := DiagnoseReports([][]int{{8, 6, 4, 4, 1}})[0]
+
+	expected := "report 1 [8 6 4 4 1]: zero step between indexes 2 and 3 (4 -> 4, delta 0)"
+	if diagnosis.String() != expected {
+		t.Errorf("Expected %q, but got %q", expected, diagnosis.String())
+	}
+}
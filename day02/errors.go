@@ -0,0 +1,56 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+type LineError struct {
+	Line int
+	Text string
+	Err  error
+}
+
+type LoadError struct {
+	Lines []LineError
+}
+
+type ShortReportsError struct {
+	// Indexes of the reports with fewer than 2 levels
+	Indexes []int
+}
+
+var ErrShortReport = errors.New("report has fewer than 2 levels")
+
+var ErrLineTooLong = fmt.Errorf("line longer than %d bytes", MaxLineLength)
+
+/************ LineError methods ************/
+
+func (e LineError) Error() string {
+	return fmt.Sprintf("line %d %q: %v", e.Line, e.Text, e.Err)
+}
+
+func (e LineError) Unwrap() error {
+	return e.Err
+}
+
+/************ LoadError methods ************/
+
+func (e LoadError) Error() string {
+	messages := make([]string, len(e.Lines))
+	for i, line := range e.Lines {
+		messages[i] = line.Error()
+	}
+	return fmt.Sprintf("%d invalid line(s): %s", len(e.Lines), strings.Join(messages, "; "))
+}
+
+/************ ShortReportsError methods ************/
+
+func (e ShortReportsError) Error() string {
+	return fmt.Sprintf("%d report(s) with fewer than 2 levels: %v", len(e.Indexes), e.Indexes)
+}
+
+func (e ShortReportsError) Unwrap() error {
+	return ErrShortReport
+}
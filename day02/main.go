@@ -1,20 +1,78 @@
 package main
 
 import (
+	"bufio"
+	"flag"
 	"fmt"
+	"io"
 	"os"
 	"strconv"
 	"strings"
 )
 
+// Longest line kept in memory, longer lines are invalid
+const MaxLineLength = 1 << 20
+
 func main() {
+	diagnose := flag.Bool("diagnose", false, "print why each unsafe report is not safe, and how to repair it")
+	stream := flag.Bool("stream", false, "read reports from stdin and print a verdict for each one as it arrives")
+	statisticsFormat := flag.String("stats", "", "print a profile of the reports, as text or json")
+	flag.Parse()
+
+	if *stream {
+		if _, err := StreamReports(os.Stdin, os.Stdout); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	reports := LoadInputs("inputs.txt")
 
+	if err := ValidateReports(reports); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	if *statisticsFormat != "" {
+		printStatistics(reports, *statisticsFormat)
+		return
+	}
+
+	if *diagnose {
+		for _, diagnosis := range DiagnoseReports(reports) {
+			fmt.Println(diagnosis)
+			if repair, ok := SuggestRepairs(diagnosis.Report); ok {
+				fmt.Println("  repair:", repair)
+			}
+		}
+		return
+	}
+
 	// print first part solution
 	fmt.Println("First part solution: ", FirstPart(reports))
 	fmt.Println("Second part solution: ", SecondPart(reports))
 }
 
+func printStatistics(reports [][]int, format string) {
+	statistics := NewStatistics(reports)
+
+	switch format {
+	case "text":
+		fmt.Print(statistics.Text())
+	case "json":
+		data, err := statistics.JSON()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		fmt.Println(string(data))
+	default:
+		fmt.Println("Unknown statistics format:", format)
+		os.Exit(1)
+	}
+}
+
 func FirstPart(reports [][]int) int {
 	return CountSafeReports(reports)
 }
@@ -26,107 +84,137 @@ func SecondPart(reports [][]int) int {
 func LoadInputs(filename string) (reports [][]int) {
 	// reading the inputs file as a matrix of integers
 	// each line is a row, and each value is a column, separated by a space
-	data, err := os.ReadFile(filename)
+	file, err := os.Open(filename)
 	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
 	}
+	defer file.Close()
 
-	lines := strings.Split(string(data), "\n")
-
-	for _, line := range lines {
-		if line == "" {
-			continue
-		}
-
-		columns := strings.Fields(line)
-		var report []int
-		for _, column := range columns {
-			value, err := strconv.Atoi(column)
-			if err != nil {
-				os.Exit(1)
-			}
-			report = append(report, value)
-		}
-		reports = append(reports, report)
+	// Invalid lines are reported, the analysis goes on with the valid ones
+	reports, err = ReadReports(file)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
 	}
 
 	return reports
 }
 
-func CountSafeReports(reports [][]int) int {
-	count := 0
-	for _, report := range reports {
-		if IsReportSafe(report) {
-			count++
+func ReadReports(input io.Reader) (reports [][]int, err error) {
+	// Returns every valid report, and a LoadError listing the invalid lines if there are any
+	var loadError LoadError
+
+	reader := bufio.NewReader(input)
+	lineNumber := 0
+	for {
+		line, tooLong, err := readLine(reader)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return reports, err
 		}
-	}
-	return count
-}
 
-func CountSafeReportsWithDampener(reports [][]int) int {
-	count := 0
-	for _, report := range reports {
-		// If the report is already safe, we don't need to remove any level
-		if IsReportSafe(report) {
-			count++
+		lineNumber++
+		if tooLong {
+			loadError.Lines = append(loadError.Lines, LineError{Line: lineNumber, Text: shortenLine(line), Err: ErrLineTooLong})
+			continue
+		}
+		if strings.TrimSpace(line) == "" {
 			continue
 		}
 
-		// If the report is not safe, we try to remove each level and check if the report is safe
-		for i := 0; i < len(report); i++ {
-			reportWithoutUnsafeLevel := RemoveIndexFromArray(report, i)
-			if IsReportSafe(reportWithoutUnsafeLevel) {
-				count++
-				break
-			}
+		report, err := parseReport(line)
+		if err != nil {
+			loadError.Lines = append(loadError.Lines, LineError{Line: lineNumber, Text: line, Err: err})
+			continue
 		}
+
+		reports = append(reports, report)
 	}
-	return count
+
+	if len(loadError.Lines) > 0 {
+		return reports, loadError
+	}
+
+	return reports, nil
 }
 
-func IsReportSafe(report []int) bool {
-	// Returns the index of the first level that is not safe
+func parseReport(line string) (report []int, err error) {
+	// Each value is a level, separated by spaces
+	columns := strings.Fields(line)
+	for _, column := range columns {
+		value, err := strconv.Atoi(column)
+		if err != nil {
+			return nil, err
+		}
+		report = append(report, value)
+	}
 
-	// A report is safe if :
-	// The levels are either all increasing or all decreasing
-	// Two adjacent levels differ by at least 1 and at most 3 ; it cannot be 0.
+	return report, nil
+}
 
-	isWithinAllowedRange := func(value int) bool {
-		const MinDifference = 1
-		const MaxDifference = 3
+func readLine(reader *bufio.Reader) (line string, tooLong bool, err error) {
+	// Reads up to the next newline. A line longer than MaxLineLength is read to its end,
+	// but only its first MaxLineLength bytes are kept.
+	var kept []byte
+	for {
+		fragment, isPrefix, err := reader.ReadLine()
+		if err == io.EOF && len(kept) > 0 {
+			// The last line has no newline and filled the buffer exactly
+			return string(kept), tooLong, nil
+		}
+		if err != nil {
+			return string(kept), tooLong, err
+		}
 
-		// convert value to positive
-		if value < 0 {
-			value = -value
+		if len(kept)+len(fragment) > MaxLineLength {
+			kept = append(kept, fragment[:MaxLineLength-len(kept)]...)
+			tooLong = true
+		} else {
+			kept = append(kept, fragment...)
 		}
 
-		return value >= MinDifference && value <= MaxDifference
+		if !isPrefix {
+			return string(kept), tooLong, nil
+		}
 	}
+}
 
-	length := len(report)
-
-	// First we check the trend.
-	firstDistance := CalculateDistance(report[0], report[1])
-	if !isWithinAllowedRange(firstDistance) {
-		return false
+func shortenLine(line string) string {
+	// Enough of an over-long line to recognise it in an error message
+	const length = 40
+	if len(line) <= length {
+		return line
 	}
+	return line[:length] + "..."
+}
 
-	isIncreasing := firstDistance > 0
-
-	// Start from the second element
-	for i := 1; i < length-1; i++ {
-		distance := CalculateDistance(report[i], report[i+1])
-		isCurrentIncreasing := distance > 0
-		if !isWithinAllowedRange(distance) {
-			return false
-		}
+func CountSafeReports(reports [][]int, policy ...SafetyPolicy) int {
+	selectedPolicy := selectPolicy(policy)
 
-		if isIncreasing != isCurrentIncreasing {
-			return false
+	count := 0
+	for _, report := range reports {
+		if IsReportSafe(report, selectedPolicy) {
+			count++
 		}
 	}
+	return count
+}
 
-	return true
+func CountSafeReportsWithDampener(reports [][]int, policy ...SafetyPolicy) int {
+	// The Problem Dampener tolerates a single bad level
+	return CountSafeReportsWithRemovals(reports, 1, policy...)
+}
+
+func IsReportSafe(report []int, policy ...SafetyPolicy) bool {
+	// A report is safe if, with the default policy :
+	// The levels are either all increasing or all decreasing
+	// Two adjacent levels differ by at least 1 and at most 3 ; it cannot be 0.
+	// DiagnoseReport tells which levels are not safe, and why.
+	// Short reports follow the policy, and count as unsafe when they are an error.
+	_, found := DiagnoseReport(report, policy...)
+	return !found
 }
 
 func CalculateDistance(a int, b int) int {
@@ -1,6 +1,8 @@
 package main
 
 import (
+	"errors"
+	"strings"
 	"testing"
 )
 
@@ -32,6 +34,81 @@ func TestLoadInputs(t *testing.T) {
 	}
 }
 
+func TestReadReports_InvalidLines(t *testing.T) {
+	// Invalid lines are listed, the other reports are still returned
+	input := "7 6 4 2 1\n1 2 x 8 9\n\n9 7 6 2 1\n1 3 2 4 5.5\n"
+
+	reports, err := ReadReports(strings.NewReader(input))
+
+	if len(reports) != 2 || reports[0][0] != 7 || reports[1][0] != 9 {
+		t.Errorf("Expected the 2 valid reports, but got %v", reports)
+	}
+
+	var loadError LoadError
+	if !errors.As(err, &loadError) {
+		t.Fatalf("Expected a LoadError, but got %v", err)
+	}
+
+	expectedLines := []int{2, 5}
+	if len(loadError.Lines) != len(expectedLines) {
+		t.Fatalf("Expected %d invalid lines, but got %v", len(expectedLines), loadError.Lines)
+	}
+	for i, expected := range expectedLines {
+		if loadError.Lines[i].Line != expected {
+			t.Errorf("Expected invalid line %d, but got %d", expected, loadError.Lines[i].Line)
+		}
+	}
+}
+
+func TestReadReports_LineTooLong(t *testing.T) {
+	// An over-long line is an invalid line, the reports after it are still read
+	input := "7 6 4 2 1\n" + strings.Repeat("1 ", MaxLineLength) + "\n9 7 6 2 1\n"
+
+	reports, err := ReadReports(strings.NewReader(input))
+
+	if len(reports) != 2 || reports[0][0] != 7 || reports[1][0] != 9 {
+		t.Errorf("Expected the 2 valid reports, but got %d reports", len(reports))
+	}
+
+	var loadError LoadError
+	if !errors.As(err, &loadError) {
+		t.Fatalf("Expected a LoadError, but got %v", err)
+	}
+	if len(loadError.Lines) != 1 || loadError.Lines[0].Line != 2 || !errors.Is(loadError.Lines[0], ErrLineTooLong) {
+		t.Errorf("Expected line 2 to be too long, but got %v", loadError)
+	}
+}
+
+func TestReadReports_LastLineFillsBuffer(t *testing.T) {
+	// A last line without newline, exactly as long as the bufio buffer, is still read
+	input := "1 2 3\n" + bufferSizedReport()
+
+	reports, err := ReadReports(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+	if len(reports) != 2 || len(reports[1]) != 2048 {
+		t.Errorf("Expected 2 reports, the last one with 2048 levels, but got %d reports", len(reports))
+	}
+}
+
+func bufferSizedReport() string {
+	// 2048 levels in exactly 4096 bytes, the default bufio buffer size, ending with "1 21"
+	report := strings.Repeat("1 2 ", 1024)
+	return report[:len(report)-1] + "1"
+}
+
+func TestReadReports_ShortReports(t *testing.T) {
+	// Single level reports are loaded, the policy decides what they are
+	reports, err := ReadReports(strings.NewReader("5\n1 2\n"))
+	if err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+	if len(reports) != 2 || len(reports[0]) != 1 {
+		t.Errorf("Expected 2 reports, the first with 1 level, but got %v", reports)
+	}
+}
+
 func TestCountSafeReports(t *testing.T) {
 	// it should return 3, since there are 3 safe reports in the test reports
 	reports := GetTestReports()
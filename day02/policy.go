@@ -0,0 +1,88 @@
+package main
+
+type Trend int
+
+const (
+	EitherTrend Trend = iota
+	Increasing
+	Decreasing
+)
+
+type ShortReportMode int
+
+const (
+	// A report with fewer than 2 levels has no step to break the rules
+	ShortReportsAreSafe ShortReportMode = iota
+	ShortReportsAreUnsafe
+	// CheckReport and ValidateReports return an error, IsReportSafe counts them as unsafe
+	ShortReportsAreErrors
+)
+
+type SafetyPolicy struct {
+	// Allowed difference between two adjacent levels, in absolute value
+	MinStep int
+	MaxStep int
+	Trend   Trend
+	// When true, two equal adjacent levels are safe and don't count for the trend
+	AllowFlat bool
+	// What to do with reports of 0 or 1 level
+	ShortReports ShortReportMode
+}
+
+func DefaultSafetyPolicy() SafetyPolicy {
+	// The puzzle rules: strictly increasing or decreasing, by 1 to 3
+	return SafetyPolicy{MinStep: 1, MaxStep: 3, Trend: EitherTrend, AllowFlat: false, ShortReports: ShortReportsAreSafe}
+}
+
+func selectPolicy(policy []SafetyPolicy) SafetyPolicy {
+	// Optional policy arguments fall back to the puzzle rules
+	if len(policy) > 0 {
+		return policy[0]
+	}
+	return DefaultSafetyPolicy()
+}
+
+/************ SafetyPolicy methods ************/
+
+func (p SafetyPolicy) stepViolation(distance int) ViolationReason {
+	if distance == 0 {
+		if p.AllowFlat {
+			return NoViolation
+		}
+		return ZeroStep
+	}
+
+	// convert value to positive
+	step := distance
+	if step < 0 {
+		step = -step
+	}
+
+	if step < p.MinStep {
+		return StepTooSmall
+	}
+	if step > p.MaxStep {
+		return StepTooLarge
+	}
+	return NoViolation
+}
+
+func (p SafetyPolicy) minimumLevels() int {
+	// Fewest levels a safe report can have
+	if p.ShortReports == ShortReportsAreSafe {
+		return 0
+	}
+	return 2
+}
+
+func (p SafetyPolicy) isTrendAllowed(trend Trend) bool {
+	return p.Trend == EitherTrend || p.Trend == trend
+}
+
+func trendOf(distance int) Trend {
+	// CalculateDistance is current - next, so a negative distance is an increase
+	if distance < 0 {
+		return Increasing
+	}
+	return Decreasing
+}
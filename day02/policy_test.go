@@ -0,0 +1,120 @@
+package main
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDefaultSafetyPolicy(t *testing.T) {
+	// The default policy gives the same results as no policy at all
+	reports := GetTestReports()
+	policy := DefaultSafetyPolicy()
+
+	if CountSafeReports(reports, policy) != CountSafeReports(reports) {
+		t.Errorf("Expected %d, but got %d", CountSafeReports(reports), CountSafeReports(reports, policy))
+	}
+	if CountSafeReportsWithDampener(reports, policy) != CountSafeReportsWithDampener(reports) {
+		t.Errorf("Expected %d, but got %d", CountSafeReportsWithDampener(reports), CountSafeReportsWithDampener(reports, policy))
+	}
+}
+
+func TestIsReportSafe_WithPolicy(t *testing.T) {
+	testValues := func(report []int, policy SafetyPolicy, expectedResult bool) {
+		result := IsReportSafe(report, policy)
+		if result != expectedResult {
+			t.Errorf("Expected %v, but got %v. Report: %v, policy: %+v", expectedResult, result, report, policy)
+		}
+	}
+
+	// A wider tolerance accepts the step from 2 to 7
+	wide := SafetyPolicy{MinStep: 1, MaxStep: 5, Trend: EitherTrend}
+	testValues([]int{1, 2, 7, 8, 9}, wide, true)
+	testValues([]int{9, 7, 6, 2, 1}, wide, true)
+
+	// A minimum of 2 rejects steps of 1
+	strict := SafetyPolicy{MinStep: 2, MaxStep: 3, Trend: EitherTrend}
+	testValues([]int{1, 3, 6, 7, 9}, strict, false)
+	testValues([]int{1, 3, 6, 9}, strict, true)
+
+	// Only increasing reports
+	increasing := SafetyPolicy{MinStep: 1, MaxStep: 3, Trend: Increasing}
+	testValues([]int{1, 3, 6, 7, 9}, increasing, true)
+	testValues([]int{7, 6, 4, 2, 1}, increasing, false)
+
+	// Only decreasing reports
+	decreasing := SafetyPolicy{MinStep: 1, MaxStep: 3, Trend: Decreasing}
+	testValues([]int{1, 3, 6, 7, 9}, decreasing, false)
+	testValues([]int{7, 6, 4, 2, 1}, decreasing, true)
+
+	// Flat steps allowed, the trend is still checked on the other steps
+	flat := SafetyPolicy{MinStep: 1, MaxStep: 3, Trend: EitherTrend, AllowFlat: true}
+	testValues([]int{8, 6, 4, 4, 1}, flat, true)
+	testValues([]int{4, 4, 5, 6}, flat, true)
+	testValues([]int{4, 4, 5, 4}, flat, false)
+}
+
+func TestCountSafeReports_WithPolicy(t *testing.T) {
+	// With flat steps allowed, 8 6 4 4 1 becomes safe too
+	reports := GetTestReports()
+	policy := DefaultSafetyPolicy()
+	policy.AllowFlat = true
+
+	if CountSafeReports(reports, policy) != 3 {
+		t.Errorf("Expected 3, but got %d", CountSafeReports(reports, policy))
+	}
+
+	// With increasing only, 1 3 6 7 9 is safe, and 1 3 2 4 5 once 3 or 2 is removed
+	policy = DefaultSafetyPolicy()
+	policy.Trend = Increasing
+
+	if CountSafeReports(reports, policy) != 1 {
+		t.Errorf("Expected 1, but got %d", CountSafeReports(reports, policy))
+	}
+	if CountSafeReportsWithDampener(reports, policy) != 2 {
+		t.Errorf("Expected 2, but got %d", CountSafeReportsWithDampener(reports, policy))
+	}
+}
+
+func TestShortReports(t *testing.T) {
+	reports := [][]int{{}, {5}, {1, 2}}
+
+	// Safe by default, with or without the dampener
+	if CountSafeReports(reports) != 3 || CountSafeReportsWithDampener(reports) != 3 {
+		t.Errorf("Expected 3 and 3, but got %d and %d", CountSafeReports(reports), CountSafeReportsWithDampener(reports))
+	}
+
+	// Unsafe, the dampener can't remove down to a single level either
+	policy := DefaultSafetyPolicy()
+	policy.ShortReports = ShortReportsAreUnsafe
+	if CountSafeReports(reports, policy) != 1 || CountSafeReportsWithDampener(reports, policy) != 1 {
+		t.Errorf("Expected 1 and 1, but got %d and %d", CountSafeReports(reports, policy), CountSafeReportsWithDampener(reports, policy))
+	}
+	if _, ok := CanBeMadeSafe([]int{1, 9}, 1, policy); ok {
+		t.Errorf("Expected 1 9 to stay unsafe when a single level is unsafe")
+	}
+
+	violation, found := DiagnoseReport([]int{5}, policy)
+	if !found || violation.Reason != TooFewLevels {
+		t.Errorf("Expected TooFewLevels, but got %+v", violation)
+	}
+
+	// Error, listed by ValidateReports and returned by CheckReport
+	policy.ShortReports = ShortReportsAreErrors
+
+	var shortError ShortReportsError
+	err := ValidateReports(reports, policy)
+	if !errors.As(err, &shortError) || len(shortError.Indexes) != 2 || !errors.Is(err, ErrShortReport) {
+		t.Errorf("Expected a ShortReportsError for reports 0 and 1, but got %v", err)
+	}
+
+	if _, err := CheckReport([]int{5}, policy); !errors.Is(err, ErrShortReport) {
+		t.Errorf("Expected ErrShortReport, but got %v", err)
+	}
+	if safe, err := CheckReport([]int{1, 2}, policy); !safe || err != nil {
+		t.Errorf("Expected 1 2 to be safe, but got %t (%v)", safe, err)
+	}
+
+	if ValidateReports(reports) != nil {
+		t.Errorf("Expected no error with the default policy")
+	}
+}
@@ -0,0 +1,255 @@
+package main
+
+import (
+	"fmt"
+)
+
+type RepairSuggestion struct {
+	// Fewest levels to delete, from the longest safe subsequence
+	MinimumRemovals int
+	RemovedIndexes  []int
+	// Fewest levels to change, and the report once they are changed
+	MinimumEdits   int
+	EditedIndexes  []int
+	RepairedReport []int
+}
+
+func SuggestRepairs(report []int, policy ...SafetyPolicy) (RepairSuggestion, bool) {
+	// Returns false when no removal or edit can make the report safe,
+	// when the policy allows no step at all or the report is too short for the policy.
+	selectedPolicy := selectPolicy(policy)
+
+	kept, ok := LongestSafeSubsequence(report, selectedPolicy)
+	if !ok {
+		return RepairSuggestion{}, false
+	}
+
+	repaired, ok := MinimumEditRepair(report, selectedPolicy)
+	if !ok {
+		return RepairSuggestion{}, false
+	}
+
+	suggestion := RepairSuggestion{
+		RemovedIndexes: missingIndexes(len(report), kept),
+		EditedIndexes:  []int{},
+		RepairedReport: repaired,
+	}
+	suggestion.MinimumRemovals = len(suggestion.RemovedIndexes)
+
+	for i := range report {
+		if report[i] != repaired[i] {
+			suggestion.EditedIndexes = append(suggestion.EditedIndexes, i)
+		}
+	}
+	suggestion.MinimumEdits = len(suggestion.EditedIndexes)
+
+	return suggestion, true
+}
+
+func LongestSafeSubsequence(report []int, policy ...SafetyPolicy) (kept []int, ok bool) {
+	// Returns the indexes of the longest safe subsequence, O(n^2) per trend
+	selectedPolicy := selectPolicy(policy)
+
+	for _, trend := range selectedPolicy.trends() {
+		candidate := longestSubsequenceForTrend(len(report), func(i int, j int) bool {
+			return selectedPolicy.isStepAllowedForTrend(report[i], report[j], trend)
+		})
+
+		if !ok || len(candidate) > len(kept) {
+			kept, ok = candidate, true
+		}
+	}
+
+	// Removing levels can't make a report long enough
+	if len(kept) < selectedPolicy.minimumLevels() {
+		return nil, false
+	}
+
+	return kept, ok
+}
+
+func MinimumEditRepair(report []int, policy ...SafetyPolicy) ([]int, bool) {
+	// Returns the report with the fewest levels changed to make it safe.
+	// Unchanged levels i < j can be kept together when the j - i steps between them
+	// can add up to their difference, which only depends on the allowed step range.
+	selectedPolicy := selectPolicy(policy)
+
+	if len(report) < 2 {
+		// Changing levels can't make a report long enough
+		if len(report) < selectedPolicy.minimumLevels() {
+			return nil, false
+		}
+		return append([]int{}, report...), true
+	}
+
+	if !selectedPolicy.hasAllowedStep() {
+		return nil, false
+	}
+
+	var best []int
+	bestEdits := -1
+	for _, trend := range selectedPolicy.trends() {
+		kept := longestSubsequenceForTrend(len(report), func(i int, j int) bool {
+			difference := report[j] - report[i]
+			if trend == Decreasing {
+				difference = -difference
+			}
+			return selectedPolicy.canSpan(difference, j-i)
+		})
+
+		repaired := selectedPolicy.fillBetweenKept(report, kept, trend)
+		edits := len(report) - len(kept)
+		if bestEdits == -1 || edits < bestEdits {
+			best, bestEdits = repaired, edits
+		}
+	}
+
+	return best, true
+}
+
+func longestSubsequenceForTrend(length int, compatible func(i int, j int) bool) []int {
+	// Classic longest chain: best[j] is the longest chain of compatible indexes ending at j
+	if length == 0 {
+		return []int{}
+	}
+
+	best := make([]int, length)
+	previous := make([]int, length)
+	last := 0
+
+	for j := 0; j < length; j++ {
+		best[j] = 1
+		previous[j] = -1
+		for i := 0; i < j; i++ {
+			if best[i]+1 > best[j] && compatible(i, j) {
+				best[j] = best[i] + 1
+				previous[j] = i
+			}
+		}
+
+		if best[j] > best[last] {
+			last = j
+		}
+	}
+
+	kept := make([]int, best[last])
+	for i, position := last, best[last]-1; i != -1; i, position = previous[i], position-1 {
+		kept[position] = i
+	}
+
+	return kept
+}
+
+func missingIndexes(length int, kept []int) []int {
+	missing := []int{}
+	next := 0
+	for i := 0; i < length; i++ {
+		if next < len(kept) && kept[next] == i {
+			next++
+			continue
+		}
+		missing = append(missing, i)
+	}
+	return missing
+}
+
+/************ RepairSuggestion methods ************/
+
+func (s RepairSuggestion) String() string {
+	return fmt.Sprintf(
+		"remove %d level(s) at %v, or change %d level(s) at %v: %v",
+		s.MinimumRemovals,
+		s.RemovedIndexes,
+		s.MinimumEdits,
+		s.EditedIndexes,
+		s.RepairedReport,
+	)
+}
+
+/************ SafetyPolicy methods ************/
+
+func (p SafetyPolicy) trends() []Trend {
+	if p.Trend == EitherTrend {
+		return []Trend{Increasing, Decreasing}
+	}
+	return []Trend{p.Trend}
+}
+
+func (p SafetyPolicy) smallestStep() int {
+	// A step of 0 is flat, and only allowed through AllowFlat
+	return max(p.MinStep, 1)
+}
+
+func (p SafetyPolicy) hasAllowedStep() bool {
+	return p.AllowFlat || p.smallestStep() <= p.MaxStep
+}
+
+func (p SafetyPolicy) nonFlatStepsFor(difference int, steps int) (int, bool) {
+	// Number of non flat steps, among steps, that can add up to difference going the trend's way
+	fewest := steps
+	if p.AllowFlat {
+		fewest = 0
+	}
+
+	for count := fewest; count <= steps; count++ {
+		if count == 0 && difference == 0 {
+			return 0, true
+		}
+		if count > 0 && count*p.smallestStep() <= difference && difference <= count*p.MaxStep {
+			return count, true
+		}
+	}
+	return 0, false
+}
+
+func (p SafetyPolicy) canSpan(difference int, steps int) bool {
+	_, ok := p.nonFlatStepsFor(difference, steps)
+	return ok
+}
+
+func (p SafetyPolicy) fillBetweenKept(report []int, kept []int, trend Trend) []int {
+	// Kept levels stay, the others are rebuilt with allowed steps going the trend's way
+	direction := 1
+	if trend == Decreasing {
+		direction = -1
+	}
+
+	repaired := append([]int{}, report...)
+
+	// Flat steps are the cheapest way to extend, otherwise the smallest step
+	extension := p.smallestStep()
+	if p.AllowFlat {
+		extension = 0
+	}
+
+	first := kept[0]
+	for i := first - 1; i >= 0; i-- {
+		repaired[i] = repaired[i+1] - direction*extension
+	}
+
+	for k := 0; k+1 < len(kept); k++ {
+		from, to := kept[k], kept[k+1]
+		steps := to - from
+		difference := (report[to] - report[from]) * direction
+
+		// Spread the difference over the non flat steps, then stay flat until the next kept level
+		count, _ := p.nonFlatStepsFor(difference, steps)
+		for s := 1; s < steps; s++ {
+			step := 0
+			if s <= count {
+				step = difference / count
+				if s <= difference%count {
+					step++
+				}
+			}
+			repaired[from+s] = repaired[from+s-1] + direction*step
+		}
+	}
+
+	last := kept[len(kept)-1]
+	for i := last + 1; i < len(report); i++ {
+		repaired[i] = repaired[i-1] + direction*extension
+	}
+
+	return repaired
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func bruteForceMinimumEdits(report []int, policy SafetyPolicy, lowest int, highest int) int {
+	// Tries every value in [lowest, highest] for the changed levels, fewest changes first
+	for edits := 0; edits <= len(report); edits++ {
+		var try func(index int, left int, current []int) bool
+		try = func(index int, left int, current []int) bool {
+			if index == len(current) {
+				return IsReportSafe(current, policy)
+			}
+
+			// The prefix must already be safe
+			if index >= 2 && !IsReportSafe(current[:index], policy) {
+				return false
+			}
+
+			if try(index+1, left, current) {
+				return true
+			}
+			if left == 0 {
+				return false
+			}
+
+			original := current[index]
+			for value := lowest; value <= highest; value++ {
+				if value == original {
+					continue
+				}
+				current[index] = value
+				if try(index+1, left-1, current) {
+					current[index] = original
+					return true
+				}
+			}
+			current[index] = original
+			return false
+		}
+
+		if try(0, edits, slices.Clone(report)) {
+			return edits
+		}
+	}
+	return -1
+}
+
+func TestSuggestRepairs(t *testing.T) {
+	testValues := func(report []int, expected RepairSuggestion) {
+		suggestion, ok := SuggestRepairs(report)
+		if !ok {
+			t.Fatalf("Expected a suggestion for %v", report)
+		}
+		if suggestion.MinimumRemovals != expected.MinimumRemovals ||
+			!slices.Equal(suggestion.RemovedIndexes, expected.RemovedIndexes) ||
+			suggestion.MinimumEdits != expected.MinimumEdits ||
+			!slices.Equal(suggestion.EditedIndexes, expected.EditedIndexes) ||
+			!slices.Equal(suggestion.RepairedReport, expected.RepairedReport) {
+			t.Errorf("Expected %+v, but got %+v. Report: %v", expected, suggestion, report)
+		}
+	}
+
+	// Already safe, nothing to do
+	testValues([]int{7, 6, 4, 2, 1}, RepairSuggestion{RemovedIndexes: []int{}, EditedIndexes: []int{}, RepairedReport: []int{7, 6, 4, 2, 1}})
+
+	// 1 2 7 8 9: removing 1 and 2, or changing 7 to 5
+	testValues([]int{1, 2, 7, 8, 9}, RepairSuggestion{
+		MinimumRemovals: 2, RemovedIndexes: []int{0, 1},
+		MinimumEdits: 1, EditedIndexes: []int{2}, RepairedReport: []int{1, 2, 5, 8, 9},
+	})
+
+	// 8 6 4 4 1: remove the second 4, or change it to 2
+	testValues([]int{8, 6, 4, 4, 1}, RepairSuggestion{
+		MinimumRemovals: 1, RemovedIndexes: []int{3},
+		MinimumEdits: 1, EditedIndexes: []int{3}, RepairedReport: []int{8, 6, 4, 2, 1},
+	})
+}
+
+func TestLongestSafeSubsequence(t *testing.T) {
+	kept, ok := LongestSafeSubsequence([]int{1, 3, 2, 4, 5})
+	if !ok || !slices.Equal(kept, []int{0, 1, 3, 4}) {
+		t.Errorf("Expected [0 1 3 4], but got %v", kept)
+	}
+
+	kept, ok = LongestSafeSubsequence([]int{})
+	if !ok || len(kept) != 0 {
+		t.Errorf("Expected an empty subsequence, but got %v", kept)
+	}
+}
+
+func TestSuggestRepairs_MatchesBruteForce(t *testing.T) {
+	random := rand.New(rand.NewSource(14))
+	policies := []SafetyPolicy{
+		DefaultSafetyPolicy(),
+		{MinStep: 2, MaxStep: 3, Trend: Decreasing},
+		{MinStep: 2, MaxStep: 2, Trend: EitherTrend, AllowFlat: true},
+	}
+
+	for round := 0; round < 150; round++ {
+		report := make([]int, 1+random.Intn(5))
+		for i := range report {
+			report[i] = random.Intn(10)
+		}
+
+		for _, policy := range policies {
+			suggestion, ok := SuggestRepairs(report, policy)
+			if !ok {
+				t.Fatalf("Expected a suggestion for %v", report)
+			}
+
+			// Removals match the k-removal dampener with no limit
+			removed, _ := CanBeMadeSafe(report, len(report), policy)
+			if suggestion.MinimumRemovals != len(removed) {
+				t.Errorf("Expected %d removals, but got %d. Report: %v, policy: %+v", len(removed), suggestion.MinimumRemovals, report, policy)
+			}
+
+			// The repaired report is safe, and no smaller edit exists
+			if !IsReportSafe(suggestion.RepairedReport, policy) {
+				t.Errorf("Expected %v to be safe. Report: %v, policy: %+v", suggestion.RepairedReport, report, policy)
+			}
+
+			expectedEdits := bruteForceMinimumEdits(report, policy, -15, 25)
+			if suggestion.MinimumEdits != expectedEdits {
+				t.Errorf("Expected %d edits, but got %d. Report: %v, policy: %+v", expectedEdits, suggestion.MinimumEdits, report, policy)
+			}
+		}
+	}
+}
+
+func TestSuggestRepairs_NoAllowedStep(t *testing.T) {
+	// Steps between 3 and 2 can't exist, only a single level is safe
+	policy := SafetyPolicy{MinStep: 3, MaxStep: 2}
+	if _, ok := SuggestRepairs([]int{1, 2}, policy); ok {
+		t.Errorf("Expected no suggestion when no step is allowed")
+	}
+}
+
+func TestSuggestRepairs_ShortReports(t *testing.T) {
+	policy := DefaultSafetyPolicy()
+	policy.ShortReports = ShortReportsAreUnsafe
+
+	// Nothing can make a single level report long enough
+	if _, ok := SuggestRepairs([]int{5}, policy); ok {
+		t.Errorf("Expected no suggestion for a single level report")
+	}
+
+	// Safe by default
+	suggestion, ok := SuggestRepairs([]int{5})
+	if !ok || suggestion.MinimumRemovals != 0 || suggestion.MinimumEdits != 0 {
+		t.Errorf("Expected nothing to repair, but got %+v (%t)", suggestion, ok)
+	}
+}
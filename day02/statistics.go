@@ -0,0 +1,171 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+type ReportClass int
+
+const (
+	// Every step is 0, or there is no step at all
+	FlatReport ReportClass = iota
+	// At least one step is larger than the policy allows, whatever the trend
+	SpikingReport
+	// Never goes down, but not always flat
+	IncreasingReport
+	// Never goes up, but not always flat
+	DecreasingReport
+	// Goes both up and down
+	OscillatingReport
+)
+
+type Statistics struct {
+	Reports int            `json:"reports"`
+	Classes map[string]int `json:"classes"`
+	// Absolute difference between two adjacent levels, and how many times it appears
+	StepSizes map[int]int `json:"stepSizes"`
+	Lengths   map[int]int `json:"lengths"`
+	Unsafe    int         `json:"unsafe"`
+	// Unsafe reports made safe by the dampener, in total and by removed index.
+	// A report is counted at every index that rescues it.
+	Rescued      int             `json:"rescued"`
+	RescuedAt    map[int]int     `json:"rescuedAt"`
+	RescueShares map[int]float64 `json:"rescueShares"`
+}
+
+func ClassifyTrend(report []int, policy ...SafetyPolicy) ReportClass {
+	selectedPolicy := selectPolicy(policy)
+
+	hasIncrease, hasDecrease := false, false
+	for i := 0; i < len(report)-1; i++ {
+		distance := CalculateDistance(report[i], report[i+1])
+		if selectedPolicy.stepViolation(distance) == StepTooLarge {
+			return SpikingReport
+		}
+
+		if distance == 0 {
+			continue
+		}
+		if trendOf(distance) == Increasing {
+			hasIncrease = true
+		} else {
+			hasDecrease = true
+		}
+	}
+
+	switch {
+	case hasIncrease && hasDecrease:
+		return OscillatingReport
+	case hasIncrease:
+		return IncreasingReport
+	case hasDecrease:
+		return DecreasingReport
+	default:
+		return FlatReport
+	}
+}
+
+func NewStatistics(reports [][]int, policy ...SafetyPolicy) Statistics {
+	selectedPolicy := selectPolicy(policy)
+
+	statistics := Statistics{
+		Reports:      len(reports),
+		Classes:      map[string]int{},
+		StepSizes:    map[int]int{},
+		Lengths:      map[int]int{},
+		RescuedAt:    map[int]int{},
+		RescueShares: map[int]float64{},
+	}
+
+	for _, report := range reports {
+		statistics.Classes[ClassifyTrend(report, selectedPolicy).String()]++
+		statistics.Lengths[len(report)]++
+
+		for i := 0; i < len(report)-1; i++ {
+			distance := CalculateDistance(report[i], report[i+1])
+			if distance < 0 {
+				distance = -distance
+			}
+			statistics.StepSizes[distance]++
+		}
+
+		if IsReportSafe(report, selectedPolicy) {
+			continue
+		}
+		statistics.Unsafe++
+
+		// Same as the dampener, one removed level at a time
+		rescued := false
+		for i := 0; i < len(report); i++ {
+			if IsReportSafe(RemoveIndexFromArray(report, i), selectedPolicy) {
+				statistics.RescuedAt[i]++
+				rescued = true
+			}
+		}
+		if rescued {
+			statistics.Rescued++
+		}
+	}
+
+	// Share of the unsafe reports that each removed index rescues
+	for index, count := range statistics.RescuedAt {
+		statistics.RescueShares[index] = float64(count) / float64(statistics.Unsafe)
+	}
+
+	return statistics
+}
+
+/************ ReportClass methods ************/
+
+func (c ReportClass) String() string {
+	switch c {
+	case FlatReport:
+		return "flat"
+	case SpikingReport:
+		return "spiking"
+	case IncreasingReport:
+		return "increasing"
+	case DecreasingReport:
+		return "decreasing"
+	default:
+		return "oscillating"
+	}
+}
+
+/************ Statistics methods ************/
+
+func (s Statistics) Text() string {
+	var builder strings.Builder
+
+	fmt.Fprintf(&builder, "Reports: %d\n", s.Reports)
+
+	builder.WriteString("Classes:\n")
+	for _, class := range []ReportClass{IncreasingReport, DecreasingReport, FlatReport, OscillatingReport, SpikingReport} {
+		fmt.Fprintf(&builder, "  %-12s %d\n", class, s.Classes[class.String()])
+	}
+
+	builder.WriteString("Step sizes:\n")
+	for _, size := range slices.Sorted(maps.Keys(s.StepSizes)) {
+		fmt.Fprintf(&builder, "  %4d %s %d\n", size, strings.Repeat("#", min(s.StepSizes[size], 50)), s.StepSizes[size])
+	}
+
+	builder.WriteString("Report lengths:\n")
+	for _, length := range slices.Sorted(maps.Keys(s.Lengths)) {
+		fmt.Fprintf(&builder, "  %4d %d\n", length, s.Lengths[length])
+	}
+
+	fmt.Fprintf(&builder, "Unsafe: %d, rescued by the dampener: %d\n", s.Unsafe, s.Rescued)
+	for _, index := range slices.Sorted(maps.Keys(s.RescuedAt)) {
+		fmt.Fprintf(&builder, "  index %d: %d (%.1f%%)\n", index, s.RescuedAt[index], 100*s.RescueShares[index])
+	}
+
+	return builder.String()
+}
+
+func (s Statistics) JSON() ([]byte, error) {
+	return json.MarshalIndent(s, "", "  ")
+}
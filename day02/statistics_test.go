@@ -0,0 +1,106 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestClassifyTrend(t *testing.T) {
+	testValues := func(report []int, expected ReportClass) {
+		if ClassifyTrend(report) != expected {
+			t.Errorf("Expected %s, but got %s. Report: %v", expected, ClassifyTrend(report), report)
+		}
+	}
+
+	testValues([]int{7, 6, 4, 2, 1}, DecreasingReport)
+	testValues([]int{1, 2, 7, 8, 9}, SpikingReport)
+	testValues([]int{9, 7, 6, 2, 1}, SpikingReport)
+	testValues([]int{1, 3, 2, 4, 5}, OscillatingReport)
+	testValues([]int{8, 6, 4, 4, 1}, DecreasingReport)
+	testValues([]int{1, 3, 6, 7, 9}, IncreasingReport)
+	testValues([]int{4, 4, 4}, FlatReport)
+	testValues([]int{4}, FlatReport)
+}
+
+func TestNewStatistics(t *testing.T) {
+	statistics := NewStatistics(GetTestReports())
+
+	expectedClasses := map[string]int{"decreasing": 2, "spiking": 2, "oscillating": 1, "increasing": 1}
+	for class, expected := range expectedClasses {
+		if statistics.Classes[class] != expected {
+			t.Errorf("Expected %d %s reports, but got %d", expected, class, statistics.Classes[class])
+		}
+	}
+
+	// 24 steps in total: 0 once, 1 ten times, 2 nine times, 3 twice, 4 once, 5 once
+	expectedSteps := map[int]int{0: 1, 1: 10, 2: 9, 3: 2, 4: 1, 5: 1}
+	for size, expected := range expectedSteps {
+		if statistics.StepSizes[size] != expected {
+			t.Errorf("Expected step size %d %d times, but got %d", size, expected, statistics.StepSizes[size])
+		}
+	}
+
+	if statistics.Lengths[5] != 6 {
+		t.Errorf("Expected 6 reports of length 5, but got %v", statistics.Lengths)
+	}
+
+	// 1 3 2 4 5 is rescued by removing index 1 or 2, 8 6 4 4 1 by removing index 2 or 3
+	if statistics.Unsafe != 4 || statistics.Rescued != 2 {
+		t.Errorf("Expected 4 unsafe and 2 rescued, but got %d and %d", statistics.Unsafe, statistics.Rescued)
+	}
+	expectedRescues := map[int]int{1: 1, 2: 2, 3: 1}
+	for index, expected := range expectedRescues {
+		if statistics.RescuedAt[index] != expected {
+			t.Errorf("Expected index %d to rescue %d reports, but got %d", index, expected, statistics.RescuedAt[index])
+		}
+	}
+	if statistics.RescueShares[2] != 0.5 {
+		t.Errorf("Expected index 2 to rescue half of the unsafe reports, but got %f", statistics.RescueShares[2])
+	}
+
+	// The rescued reports are the ones the dampener adds
+	if statistics.Reports-statistics.Unsafe+statistics.Rescued != SecondPart(GetTestReports()) {
+		t.Errorf("Expected the statistics to match SecondPart")
+	}
+}
+
+func TestStatistics_Text(t *testing.T) {
+	statistics := NewStatistics([][]int{{1, 2, 3}, {3, 1, 2}})
+
+	expected := "" +
+		"Reports: 2\n" +
+		"Classes:\n" +
+		"  increasing   1\n" +
+		"  decreasing   0\n" +
+		"  flat         0\n" +
+		"  oscillating  1\n" +
+		"  spiking      0\n" +
+		"Step sizes:\n" +
+		"     1 ### 3\n" +
+		"     2 # 1\n" +
+		"Report lengths:\n" +
+		"     3 2\n" +
+		"Unsafe: 1, rescued by the dampener: 1\n" +
+		"  index 0: 1 (100.0%)\n" +
+		"  index 1: 1 (100.0%)\n" +
+		"  index 2: 1 (100.0%)\n"
+
+	if statistics.Text() != expected {
+		t.Errorf("Expected\n%s\nbut got\n%s", expected, statistics.Text())
+	}
+}
+
+func TestStatistics_JSON(t *testing.T) {
+	data, err := NewStatistics(GetTestReports()).JSON()
+	if err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+
+	var decoded Statistics
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Expected valid JSON, but got %v", err)
+	}
+	if decoded.Reports != 6 || decoded.RescuedAt[2] != 2 {
+		t.Errorf("Expected 6 reports and 2 rescued at index 2, but got %+v", decoded)
+	}
+}
@@ -0,0 +1,103 @@
+package main
+
+type DampenerKind int
+
+const (
+	// Remove any single level, the Problem Dampener
+	AnySingleLevel DampenerKind = iota
+	// Remove only the first or the last level
+	EdgeLevel
+	// Remove one contiguous run of up to MaxRun levels
+	ContiguousRun
+	// Set one level to the value of a neighbouring level, instead of removing it.
+	// It always makes a flat step, so it never rescues a report under DefaultSafetyPolicy,
+	// only under a policy with AllowFlat.
+	ClampLevel
+)
+
+type DampenerStrategy struct {
+	Kind DampenerKind
+	// Longest run removed by ContiguousRun
+	MaxRun int
+}
+
+func CountSafeReportsWithStrategy(reports [][]int, strategy DampenerStrategy, policy ...SafetyPolicy) int {
+	// Same as CountSafeReportsWithDampener, with another tolerance model
+	selectedPolicy := selectPolicy(policy)
+
+	count := 0
+	for _, report := range reports {
+		if strategy.Rescues(report, selectedPolicy) {
+			count++
+		}
+	}
+	return count
+}
+
+/************ DampenerStrategy methods ************/
+
+func (s DampenerStrategy) Rescues(report []int, policy ...SafetyPolicy) bool {
+	// True when the report is safe, or can be made safe by the strategy
+	selectedPolicy := selectPolicy(policy)
+
+	if IsReportSafe(report, selectedPolicy) {
+		return true
+	}
+
+	switch s.Kind {
+	case AnySingleLevel:
+		_, ok := CanBeMadeSafe(report, 1, selectedPolicy)
+		return ok
+	case EdgeLevel:
+		return rescuedByEdgeRemoval(report, selectedPolicy)
+	case ContiguousRun:
+		return rescuedByRunRemoval(report, s.MaxRun, selectedPolicy)
+	case ClampLevel:
+		return rescuedByClamping(report, selectedPolicy)
+	default:
+		return false
+	}
+}
+
+func rescuedByEdgeRemoval(report []int, policy SafetyPolicy) bool {
+	if len(report) == 0 {
+		return false
+	}
+
+	return IsReportSafe(report[1:], policy) || IsReportSafe(report[:len(report)-1], policy)
+}
+
+func rescuedByRunRemoval(report []int, maxRun int, policy SafetyPolicy) bool {
+	for start := 0; start < len(report); start++ {
+		for length := 1; length <= maxRun && start+length <= len(report); length++ {
+			remaining := append(append([]int{}, report[:start]...), report[start+length:]...)
+			if IsReportSafe(remaining, policy) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func rescuedByClamping(report []int, policy SafetyPolicy) bool {
+	// Each level can take the value of the level before or after it
+	clamped := append([]int{}, report...)
+
+	for i := range report {
+		for _, neighbour := range []int{i - 1, i + 1} {
+			if neighbour < 0 || neighbour >= len(report) {
+				continue
+			}
+
+			clamped[i] = report[neighbour]
+			if IsReportSafe(clamped, policy) {
+				return true
+			}
+		}
+
+		clamped[i] = report[i]
+	}
+
+	return false
+}
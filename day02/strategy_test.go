@@ -0,0 +1,153 @@
+package main
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func bruteForceRescuedByRemovals(report []int, policy SafetyPolicy, allowed func(removed []int) bool) bool {
+	// Tries every set of removed levels that the strategy allows
+	for mask := 0; mask < 1<<len(report); mask++ {
+		var removed []int
+		var kept []int
+		for i, level := range report {
+			if mask&(1<<i) != 0 {
+				removed = append(removed, i)
+			} else {
+				kept = append(kept, level)
+			}
+		}
+
+		if allowed(removed) && IsReportSafe(kept, policy) {
+			return true
+		}
+	}
+	return false
+}
+
+func bruteForceRescuedByClamping(report []int, policy SafetyPolicy) bool {
+	// Builds every report where one level of an adjacent pair is replaced by a copy of the other,
+	// by removing it and inserting the copy, then checks it with its own reading of the policy
+	if bruteForceIsSafe(report, policy) {
+		return true
+	}
+
+	for left := 0; left+1 < len(report); left++ {
+		right := left + 1
+		candidates := [][]int{
+			slices.Insert(RemoveIndexFromArray(report, right), right, report[left]),
+			slices.Insert(RemoveIndexFromArray(report, left), left, report[right]),
+		}
+
+		for _, candidate := range candidates {
+			if bruteForceIsSafe(candidate, policy) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func bruteForceIsSafe(report []int, policy SafetyPolicy) bool {
+	// Every step goes the same way, or is flat when allowed, and its size is within the bounds
+	if len(report) < 2 {
+		return policy.ShortReports == ShortReportsAreSafe
+	}
+
+	for _, direction := range []int{1, -1} {
+		if (direction == 1 && policy.Trend == Decreasing) || (direction == -1 && policy.Trend == Increasing) {
+			continue
+		}
+
+		safe := true
+		for i := 0; i+1 < len(report); i++ {
+			step := (report[i+1] - report[i]) * direction
+			if step == 0 {
+				safe = safe && policy.AllowFlat
+			} else {
+				safe = safe && step >= policy.MinStep && step <= policy.MaxStep
+			}
+		}
+		if safe {
+			return true
+		}
+	}
+	return false
+}
+
+func TestCountSafeReportsWithStrategy(t *testing.T) {
+	reports := GetTestReports()
+
+	testValues := func(strategy DampenerStrategy, expected int, policy ...SafetyPolicy) {
+		count := CountSafeReportsWithStrategy(reports, strategy, policy...)
+		if count != expected {
+			t.Errorf("Expected %d for %+v, but got %d", expected, strategy, count)
+		}
+	}
+
+	// Any single level is the Problem Dampener
+	testValues(DampenerStrategy{Kind: AnySingleLevel}, SecondPart(reports))
+	// No unsafe test report is fixed by dropping an edge
+	testValues(DampenerStrategy{Kind: EdgeLevel}, 2)
+	// A run of 1 is a single level, a run of 2 also fixes 1 2 7 8 9 by dropping 1 2
+	testValues(DampenerStrategy{Kind: ContiguousRun, MaxRun: 1}, 4)
+	testValues(DampenerStrategy{Kind: ContiguousRun, MaxRun: 2}, 6)
+	// Taking a neighbour's value makes a flat step, so only the safe reports are left
+	testValues(DampenerStrategy{Kind: ClampLevel}, 2)
+	// With flat steps, 8 6 4 4 1 is safe and 1 3 2 4 5 is fixed by setting 2 to 3
+	flatPolicy := DefaultSafetyPolicy()
+	flatPolicy.AllowFlat = true
+	testValues(DampenerStrategy{Kind: ClampLevel}, 4, flatPolicy)
+}
+
+func TestDampenerStrategy_MatchesBruteForce(t *testing.T) {
+	random := rand.New(rand.NewSource(18))
+	policies := []SafetyPolicy{
+		DefaultSafetyPolicy(),
+		{MinStep: 2, MaxStep: 3, Trend: Increasing},
+		{MinStep: 1, MaxStep: 2, Trend: EitherTrend, AllowFlat: true},
+	}
+
+	isEdge := func(report []int) func(removed []int) bool {
+		return func(removed []int) bool {
+			return len(removed) == 0 || (len(removed) == 1 && (removed[0] == 0 || removed[0] == len(report)-1))
+		}
+	}
+	isRun := func(maxRun int) func(removed []int) bool {
+		return func(removed []int) bool {
+			return len(removed) <= maxRun && (len(removed) == 0 || removed[len(removed)-1]-removed[0] == len(removed)-1)
+		}
+	}
+	isSingle := func(removed []int) bool {
+		return len(removed) <= 1
+	}
+
+	for round := 0; round < 400; round++ {
+		report := make([]int, 2+random.Intn(6))
+		level := random.Intn(20)
+		for i := range report {
+			level += random.Intn(9) - 4
+			report[i] = level
+		}
+
+		for _, policy := range policies {
+			checks := []struct {
+				strategy DampenerStrategy
+				expected bool
+			}{
+				{DampenerStrategy{Kind: AnySingleLevel}, bruteForceRescuedByRemovals(report, policy, isSingle)},
+				{DampenerStrategy{Kind: EdgeLevel}, bruteForceRescuedByRemovals(report, policy, isEdge(report))},
+				{DampenerStrategy{Kind: ContiguousRun, MaxRun: 2}, bruteForceRescuedByRemovals(report, policy, isRun(2))},
+				{DampenerStrategy{Kind: ContiguousRun, MaxRun: 3}, bruteForceRescuedByRemovals(report, policy, isRun(3))},
+				{DampenerStrategy{Kind: ClampLevel}, bruteForceRescuedByClamping(report, policy)},
+			}
+
+			for _, check := range checks {
+				if check.strategy.Rescues(report, policy) != check.expected {
+					t.Errorf("Expected %t for %+v. Report: %v, policy: %+v", check.expected, check.strategy, report, policy)
+				}
+			}
+		}
+	}
+}
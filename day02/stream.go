@@ -0,0 +1,124 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+type Verdict int
+
+const (
+	Safe Verdict = iota
+	SafeWithDampener
+	Unsafe
+	// The line is not a report, or a short report with ShortReportsAreErrors
+	Invalid
+)
+
+type StreamCounters struct {
+	Reports          int
+	Safe             int
+	SafeWithDampener int
+	Invalid          int
+}
+
+func StreamReports(input io.Reader, output io.Writer, policy ...SafetyPolicy) (StreamCounters, error) {
+	// Reads one report per line and writes its verdict with both running totals, e.g. "safe 3 4".
+	// Only the current line is held in memory, however long the input is, and at most MaxLineLength bytes of it.
+	selectedPolicy := selectPolicy(policy)
+
+	var counters StreamCounters
+	reader := bufio.NewReader(input)
+	for {
+		line, tooLong, err := readLine(reader)
+		if err == io.EOF {
+			return counters, nil
+		}
+		if err != nil {
+			return counters, err
+		}
+
+		if !tooLong && strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		// An over-long line is not a report, whatever its first bytes are
+		verdict := Invalid
+		if !tooLong {
+			verdict = ClassifyReportLine(line, selectedPolicy)
+		}
+		counters.add(verdict)
+
+		if _, err := fmt.Fprintf(output, "%s %d %d\n", verdict, counters.FirstPart(), counters.SecondPart()); err != nil {
+			return counters, err
+		}
+	}
+}
+
+func ClassifyReportLine(line string, policy ...SafetyPolicy) Verdict {
+	selectedPolicy := selectPolicy(policy)
+
+	report, err := parseReport(line)
+	if err != nil {
+		return Invalid
+	}
+
+	return ClassifyReport(report, selectedPolicy)
+}
+
+func ClassifyReport(report []int, policy ...SafetyPolicy) Verdict {
+	selectedPolicy := selectPolicy(policy)
+
+	safe, err := CheckReport(report, selectedPolicy)
+	if err != nil {
+		return Invalid
+	}
+	if safe {
+		return Safe
+	}
+
+	if _, ok := CanBeMadeSafe(report, 1, selectedPolicy); ok {
+		return SafeWithDampener
+	}
+	return Unsafe
+}
+
+/************ Verdict methods ************/
+
+func (v Verdict) String() string {
+	switch v {
+	case Safe:
+		return "safe"
+	case SafeWithDampener:
+		return "safe-with-dampener"
+	case Unsafe:
+		return "unsafe"
+	default:
+		return "invalid"
+	}
+}
+
+/************ StreamCounters methods ************/
+
+func (c *StreamCounters) add(verdict Verdict) {
+	c.Reports++
+	switch verdict {
+	case Safe:
+		c.Safe++
+	case SafeWithDampener:
+		c.SafeWithDampener++
+	case Invalid:
+		c.Invalid++
+	}
+}
+
+func (c StreamCounters) FirstPart() int {
+	return c.Safe
+}
+
+func (c StreamCounters) SecondPart() int {
+	// Safe reports are also safe with the dampener
+	return c.Safe + c.SafeWithDampener
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"io"
+	"math/rand"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestStreamReports(t *testing.T) {
+	file, err := os.Open(DefaultTestInputFile)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+	defer file.Close()
+
+	var output strings.Builder
+	counters, err := StreamReports(file, &output)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+
+	expected := "" +
+		"safe 1 1\n" +
+		"unsafe 1 1\n" +
+		"unsafe 1 1\n" +
+		"safe-with-dampener 1 2\n" +
+		"safe-with-dampener 1 3\n" +
+		"safe 2 4\n"
+
+	if output.String() != expected {
+		t.Errorf("Expected\n%s\nbut got\n%s", expected, output.String())
+	}
+
+	if counters.FirstPart() != FirstPart(GetTestReports()) || counters.SecondPart() != SecondPart(GetTestReports()) {
+		t.Errorf("Expected %d and %d, but got %d and %d", FirstPart(GetTestReports()), SecondPart(GetTestReports()), counters.FirstPart(), counters.SecondPart())
+	}
+}
+
+func TestStreamReports_InvalidLines(t *testing.T) {
+	// Invalid lines get a verdict and don't change the totals
+	var output strings.Builder
+	counters, err := StreamReports(strings.NewReader("7 6 4 2 1\n1 x 3\n\n1 3 6 7 9\n"), &output)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+
+	expected := "safe 1 1\ninvalid 1 1\nsafe 2 2\n"
+	if output.String() != expected {
+		t.Errorf("Expected\n%s\nbut got\n%s", expected, output.String())
+	}
+	if counters.Reports != 3 || counters.Invalid != 1 {
+		t.Errorf("Expected 3 reports and 1 invalid, but got %+v", counters)
+	}
+}
+
+func TestStreamReports_LineTooLong(t *testing.T) {
+	// An over-long line is invalid, the stream goes on with the next report
+	input := strings.Repeat("1 ", MaxLineLength) + "\n7 6 4 2 1\n"
+
+	var output strings.Builder
+	counters, err := StreamReports(strings.NewReader(input), &output)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+
+	expected := "invalid 0 0\nsafe 1 1\n"
+	if output.String() != expected {
+		t.Errorf("Expected\n%s\nbut got\n%s", expected, output.String())
+	}
+	if counters.Reports != 2 || counters.Invalid != 1 {
+		t.Errorf("Expected 2 reports and 1 invalid, but got %+v", counters)
+	}
+}
+
+func TestStreamReports_LastLineFillsBuffer(t *testing.T) {
+	// The last line gets a verdict, and the totals match both parts
+	input := "1 2 3\n" + bufferSizedReport()
+	reports, _ := ReadReports(strings.NewReader(input))
+
+	var output strings.Builder
+	counters, err := StreamReports(strings.NewReader(input), &output)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+
+	expected := "safe 1 1\nunsafe 1 1\n"
+	if output.String() != expected {
+		t.Errorf("Expected\n%s\nbut got\n%s", expected, output.String())
+	}
+	if counters.Reports != 2 || counters.FirstPart() != FirstPart(reports) || counters.SecondPart() != SecondPart(reports) {
+		t.Errorf("Expected 2 reports matching both parts, but got %+v", counters)
+	}
+}
+
+func TestStreamReports_MatchesPrefixes(t *testing.T) {
+	// After each line, the totals match both parts on the reports read so far
+	random := rand.New(rand.NewSource(16))
+
+	var reports [][]int
+	var lines []string
+	for i := 0; i < 200; i++ {
+		report := make([]int, 2+random.Intn(6))
+		var fields []string
+		for j := range report {
+			report[j] = random.Intn(12)
+			fields = append(fields, strconv.Itoa(report[j]))
+		}
+		reports = append(reports, report)
+		lines = append(lines, strings.Join(fields, " "))
+	}
+
+	var output strings.Builder
+	_, err := StreamReports(strings.NewReader(strings.Join(lines, "\n")), &output)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+
+	for i, line := range strings.Split(strings.TrimSpace(output.String()), "\n") {
+		fields := strings.Fields(line)
+		first, _ := strconv.Atoi(fields[1])
+		second, _ := strconv.Atoi(fields[2])
+
+		prefix := reports[:i+1]
+		if first != FirstPart(prefix) || second != SecondPart(prefix) {
+			t.Fatalf("Line %d: expected %d and %d, but got %q", i, FirstPart(prefix), SecondPart(prefix), line)
+		}
+	}
+}
+
+type repeatingReader struct {
+	line      []byte
+	remaining int
+	offset    int
+}
+
+func (r *repeatingReader) Read(buffer []byte) (int, error) {
+	if r.remaining == 0 {
+		return 0, io.EOF
+	}
+
+	n := copy(buffer, r.line[r.offset:])
+	r.offset += n
+	if r.offset == len(r.line) {
+		r.offset = 0
+		r.remaining--
+	}
+	return n, nil
+}
+
+func TestStreamReports_LongInput(t *testing.T) {
+	// Many lines go through without keeping them, only the counters grow
+	reader := &repeatingReader{line: []byte("1 3 2 4 5\n"), remaining: 100000}
+
+	counters, err := StreamReports(reader, io.Discard)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+	if counters.Reports != 100000 || counters.SecondPart() != 100000 || counters.FirstPart() != 0 {
+		t.Errorf("Expected 100000 reports safe with the dampener, but got %+v", counters)
+	}
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+type ControlFlowMode int
+
+const (
+	// do() enables and don't() disables, depth is 1 after a don't(), 0 otherwise
+	ToggleControlFlow ControlFlowMode = iota
+	// Each don't() opens a disabled scope and each do() closes one, depth is the number of open scopes.
+	// Enabled when no scope is open, a do() without an open scope does nothing.
+	StackControlFlow
+	// do() adds an enable level and don't() removes one, depth is the level.
+	// Starts at 1 and enabled while the level is above 0, extra do() are kept for later don't().
+	CountedControlFlow
+)
+
+// ScopeStep class, the machine after one instruction
+type ScopeStep struct {
+	token    Token
+	executed bool
+	depth    int
+	enabled  bool
+}
+
+func newMachine(controlFlow ControlFlowMode) Machine {
+	machine := Machine{enabled: true, controlFlow: controlFlow}
+	if controlFlow == CountedControlFlow {
+		machine.depth = 1
+	}
+	return machine
+}
+
+func scopeDump(steps []ScopeStep) string {
+	// One line per instruction, indented by its depth
+	var builder strings.Builder
+	for _, step := range steps {
+		state := "enabled"
+		if !step.enabled {
+			state = "disabled"
+		}
+
+		fmt.Fprintf(&builder, "%-8s depth %d %-8s %s%s\n",
+			step.token.Position, step.depth, state, strings.Repeat("  ", max(step.depth, 0)), step.token.Text)
+	}
+	return builder.String()
+}
+
+// Machine methods
+func (m *Machine) enable() {
+	switch m.controlFlow {
+	case StackControlFlow:
+		m.depth = max(m.depth-1, 0)
+		m.enabled = m.depth == 0
+	case CountedControlFlow:
+		m.depth++
+		m.enabled = m.depth > 0
+	default:
+		m.depth = 0
+		m.enabled = true
+	}
+}
+
+func (m *Machine) disable() {
+	switch m.controlFlow {
+	case StackControlFlow:
+		m.depth++
+		m.enabled = false
+	case CountedControlFlow:
+		m.depth--
+		m.enabled = m.depth > 0
+	default:
+		m.depth = 1
+		m.enabled = false
+	}
+}
+
+// Interpreter methods
+func (i *Interpreter) traceScopes(memory string) []ScopeStep {
+	// The scope depth after every instruction
+	tokens, _ := newLexer(memory, i.syntax()).tokenize()
+
+	steps := []ScopeStep{}
+	machine := i.newMachine()
+	for _, token := range tokens {
+		executed := i.step(&machine, token)
+		steps = append(steps, ScopeStep{token, executed, machine.depth, machine.enabled})
+	}
+	return steps
+}
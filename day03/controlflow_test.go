@@ -0,0 +1,59 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const nestedTestMemory = "mul(1,1)don't()don't()mul(2,2)do()mul(3,3)do()mul(4,4)do()do()don't()mul(5,5)"
+
+func TestControlFlowModes(t *testing.T) {
+	testValues := func(controlFlow ControlFlowMode, expected int, expectedDepths []int) {
+		if result := secondPart(nestedTestMemory, controlFlow); result != expected {
+			t.Errorf("Expected %d for mode %d, but got %d", expected, controlFlow, result)
+		}
+
+		totals, _ := scanStream(strings.NewReader(nestedTestMemory), 7, controlFlow)
+		if totals.secondPart != expected {
+			t.Errorf("Expected %d for mode %d when streaming, but got %d", expected, controlFlow, totals.secondPart)
+		}
+
+		depths := []int{}
+		for _, step := range secondPartInterpreter(controlFlow).traceScopes(nestedTestMemory) {
+			depths = append(depths, step.depth)
+		}
+		if !reflect.DeepEqual(depths, expectedDepths) {
+			t.Errorf("Expected %v for mode %d, but got %v", expectedDepths, controlFlow, depths)
+		}
+	}
+
+	// The last don't() wins: 1 + 9 + 16
+	testValues(ToggleControlFlow, 26, []int{0, 1, 1, 1, 0, 0, 0, 0, 0, 0, 1, 1})
+	// Two do() close the two scopes, the extra ones do nothing: 1 + 16
+	testValues(StackControlFlow, 17, []int{0, 1, 2, 2, 1, 1, 0, 0, 0, 0, 1, 1})
+	// The extra do() make up for the last don't(): 1 + 16 + 25
+	testValues(CountedControlFlow, 42, []int{1, 0, -1, -1, 0, 0, 1, 1, 2, 3, 2, 2})
+}
+
+func TestControlFlowModes_DefaultIsToggle(t *testing.T) {
+	memory := loadMemory(DefaultTestInputFile)
+
+	if result := secondPart(memory, ToggleControlFlow); result != secondPart(memory) {
+		t.Errorf("Expected %d, but got %d", secondPart(memory), result)
+	}
+}
+
+func TestScopeDump(t *testing.T) {
+	steps := secondPartInterpreter(StackControlFlow).traceScopes("mul(2,4)don't()\ndon't()mul(1,1)do()")
+
+	expected := "1:1      depth 0 enabled  mul(2,4)\n" +
+		"1:9      depth 1 disabled   don't()\n" +
+		"2:1      depth 2 disabled     don't()\n" +
+		"2:8      depth 2 disabled     mul(1,1)\n" +
+		"2:16     depth 1 disabled   do()\n"
+
+	if result := scopeDump(steps); result != expected {
+		t.Errorf("Expected %q, but got %q", expected, result)
+	}
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+)
+
+// Fragments that look like instructions but aren't, %d is an operand of 1 to 3 digits
+var nearMissTemplates = []string{
+	// Wrong brackets
+	"mul[%d,%d]", "mul(%d,%d]", "mul{%d,%d}", "mul<%d,%d)", "do[]", "don't(]",
+	// 4 digit operands
+	"mul(1%03d,%d)", "mul(%d,9%03d)",
+	// Spaces
+	"mul (%d,%d)", "mul(%d, %d)", "mul( %d,%d)", "do ()", "don't( )",
+	// Partial or misspelled keywords
+	"mu(%d,%d)", "ul(%d,%d)", "mull(%d,%d)", "dont()", "do_not()", "don'()",
+	// Missing or extra operands, and unfinished calls
+	"mul(,%d)", "mul(%d)", "mul(%d,%d,%d)", "do(%d)", "mul(%d,%d",
+}
+
+// Between two fragments, never part of an instruction
+const fillerCharacters = "!@#$%^&*+-_=?~|;:/\\.\n"
+
+// GeneratorOptions class
+type GeneratorOptions struct {
+	Seed       int64
+	Muls       int
+	Dos        int
+	Donts      int
+	NearMisses int
+	// Most filler characters between two fragments, at least 1
+	MaxFiller int
+}
+
+// GeneratedMemory class, corrupted memory with its known answers
+type GeneratedMemory struct {
+	memory     string
+	pairs      []Pair
+	firstPart  int
+	secondPart int
+}
+
+func defaultGeneratorOptions() GeneratorOptions {
+	return GeneratorOptions{Seed: 1, Muls: 100, Dos: 10, Donts: 10, NearMisses: 100, MaxFiller: 4}
+}
+
+func generateMemory(options GeneratorOptions) GeneratedMemory {
+	// The answers come from the generated instructions, never from parsing the memory
+	random := rand.New(rand.NewSource(options.Seed))
+
+	const (
+		mulFragment = iota
+		doFragment
+		dontFragment
+		nearMissFragment
+	)
+
+	fragments := []int{}
+	for kind, count := range []int{options.Muls, options.Dos, options.Donts, options.NearMisses} {
+		for i := 0; i < count; i++ {
+			fragments = append(fragments, kind)
+		}
+	}
+	random.Shuffle(len(fragments), func(i int, j int) {
+		fragments[i], fragments[j] = fragments[j], fragments[i]
+	})
+
+	generated := GeneratedMemory{pairs: []Pair{}}
+	var builder strings.Builder
+	writeFiller := func() {
+		for count := 1 + random.Intn(max(options.MaxFiller, 1)); count > 0; count-- {
+			builder.WriteByte(fillerCharacters[random.Intn(len(fillerCharacters))])
+		}
+	}
+
+	// Always start with enabled
+	enabled := true
+	writeFiller()
+	for _, fragment := range fragments {
+		switch fragment {
+		case mulFragment:
+			pair := Pair{random.Intn(1000), random.Intn(1000), enabled}
+			generated.pairs = append(generated.pairs, pair)
+			generated.firstPart += pair.first * pair.second
+			if enabled {
+				generated.secondPart += pair.first * pair.second
+			}
+			fmt.Fprintf(&builder, "mul(%d,%d)", pair.first, pair.second)
+		case doFragment:
+			enabled = true
+			builder.WriteString("do()")
+		case dontFragment:
+			enabled = false
+			builder.WriteString("don't()")
+		default:
+			template := nearMissTemplates[random.Intn(len(nearMissTemplates))]
+			operands := make([]any, strings.Count(template, "%"))
+			for i := range operands {
+				operands[i] = random.Intn(1000)
+			}
+			fmt.Fprintf(&builder, template, operands...)
+		}
+		writeFiller()
+	}
+
+	generated.memory = builder.String()
+
+	return generated
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGenerateMemory_Deterministic(t *testing.T) {
+	options := defaultGeneratorOptions()
+
+	first, second := generateMemory(options), generateMemory(options)
+	if !reflect.DeepEqual(first, second) {
+		t.Errorf("Expected the same memory for seed %d", options.Seed)
+	}
+
+	options.Seed++
+	if generateMemory(options).memory == first.memory {
+		t.Errorf("Expected another memory for seed %d", options.Seed)
+	}
+}
+
+func TestGenerateMemory_Counts(t *testing.T) {
+	options := GeneratorOptions{Seed: 7, Muls: 30, Dos: 5, Donts: 6, NearMisses: 40, MaxFiller: 3}
+	generated := generateMemory(options)
+
+	tokens, _ := newLexer(generated.memory).tokenize()
+	counts := map[string]int{}
+	for _, token := range tokens {
+		counts[token.Name]++
+	}
+
+	expected := map[string]int{"mul": 30, "do": 5, "don't": 6}
+	if !reflect.DeepEqual(counts, expected) {
+		t.Errorf("Expected %v, but got %v", expected, counts)
+	}
+}
+
+func TestGenerateMemory_NearMisses(t *testing.T) {
+	// Only near misses, none of them may be read as an instruction
+	generated := generateMemory(GeneratorOptions{Seed: 25, NearMisses: 2000, MaxFiller: 2})
+
+	tokens, nearMisses := newLexer(generated.memory).tokenize()
+	if len(tokens) != 0 {
+		t.Errorf("Expected no instruction, but got %v", tokens)
+	}
+	if len(nearMisses) == 0 {
+		t.Errorf("Expected near misses in %q", generated.memory)
+	}
+}
+
+func TestMultiplyAndSumPairs_MatchesGenerator(t *testing.T) {
+	// Property test: the parsed pairs and both sums match the generator's ground truth
+	for seed := int64(0); seed < 200; seed++ {
+		options := defaultGeneratorOptions()
+		options.Seed = seed
+		generated := generateMemory(options)
+
+		tokens, _ := newLexer(generated.memory).tokenize()
+		pairs := pairsFromTokens(tokens)
+		if !reflect.DeepEqual(pairs, generated.pairs) {
+			t.Fatalf("Expected %v, but got %v for seed %d", generated.pairs, pairs, seed)
+		}
+
+		if result := multiplyAndSumPairs(pairs, false); result != generated.firstPart {
+			t.Errorf("Expected %d, but got %d for seed %d", generated.firstPart, result, seed)
+		}
+		if result := multiplyAndSumPairs(pairs, true); result != generated.secondPart {
+			t.Errorf("Expected %d, but got %d for seed %d", generated.secondPart, result, seed)
+		}
+
+		// The other solvers must agree too
+		if firstPart(generated.memory) != generated.firstPart || secondPart(generated.memory) != generated.secondPart {
+			t.Errorf("Expected %d and %d from the interpreter for seed %d", generated.firstPart, generated.secondPart, seed)
+		}
+
+		totals, err := scanStream(strings.NewReader(generated.memory), 1+int(seed)%17)
+		if err != nil || totals.firstPart != generated.firstPart || totals.secondPart != generated.secondPart {
+			t.Errorf("Expected %d and %d from the stream for seed %d, but got %+v", generated.firstPart, generated.secondPart, seed, totals)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package main
+
+// Machine class, the state instructions work on
+type Machine struct {
+	accumulator int
+	enabled     bool
+	controlFlow ControlFlowMode
+	// Meaning depends on controlFlow, see ControlFlowMode
+	depth int
+}
+
+type InstructionHandler func(machine *Machine, operands []int)
+
+// Instruction class
+type Instruction struct {
+	operands int
+	handler  InstructionHandler
+	// Control instructions run even when the machine is disabled
+	control bool
+}
+
+// Interpreter class, a registry of instructions
+type Interpreter struct {
+	instructions map[string]Instruction
+	// When false, disabled instructions still run, like in the first part
+	honourEnabled bool
+	controlFlow   ControlFlowMode
+}
+
+func newInterpreter(honourEnabled bool) *Interpreter {
+	// mul, do and don't are built in, other instructions can be registered
+	interpreter := &Interpreter{instructions: map[string]Instruction{}, honourEnabled: honourEnabled}
+
+	interpreter.register("mul", 2, mulHandler)
+	interpreter.register("do", 0, doHandler, true)
+	interpreter.register("don't", 0, dontHandler, true)
+
+	return interpreter
+}
+
+func firstPartInterpreter() *Interpreter {
+	// Every mul counts
+	return newInterpreter(false)
+}
+
+func secondPartInterpreter(controlFlow ...ControlFlowMode) *Interpreter {
+	// mul only counts while enabled, by default between do() and don't()
+	interpreter := newInterpreter(true)
+	if len(controlFlow) > 0 {
+		interpreter.controlFlow = controlFlow[0]
+	}
+	return interpreter
+}
+
+func mulHandler(machine *Machine, operands []int) {
+	machine.accumulator += operands[0] * operands[1]
+}
+
+func doHandler(machine *Machine, operands []int) {
+	machine.enable()
+}
+
+func dontHandler(machine *Machine, operands []int) {
+	machine.disable()
+}
+
+// Interpreter methods
+func (i *Interpreter) register(name string, operands int, handler InstructionHandler, control ...bool) {
+	// Replaces any instruction with the same name
+	i.instructions[name] = Instruction{
+		operands: operands,
+		handler:  handler,
+		control:  len(control) > 0 && control[0],
+	}
+}
+
+func (i *Interpreter) syntax() map[string]int {
+	// Names and operand counts for the lexer
+	syntax := map[string]int{}
+	for name, instruction := range i.instructions {
+		syntax[name] = instruction.operands
+	}
+	return syntax
+}
+
+func (i *Interpreter) run(memory string) Machine {
+	tokens, _ := newLexer(memory, i.syntax()).tokenize()
+	return i.execute(tokens)
+}
+
+func (i *Interpreter) execute(tokens []Token) Machine {
+	machine := i.newMachine()
+	for _, token := range tokens {
+		i.step(&machine, token)
+	}
+	return machine
+}
+
+func (i *Interpreter) newMachine() Machine {
+	// Always start with enabled
+	return newMachine(i.controlFlow)
+}
+
+func (i *Interpreter) step(machine *Machine, token Token) (executed bool) {
+	// Returns false when the instruction is unknown, or skipped because the machine is disabled
+	instruction, ok := i.instructions[token.Name]
+	if !ok {
+		return false
+	}
+
+	if i.honourEnabled && !machine.enabled && !instruction.control {
+		return false
+	}
+
+	instruction.handler(machine, token.Operands)
+	return true
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"math/rand"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// Instructions outside the puzzle, registered by the tests only
+func addHandler(machine *Machine, operands []int) {
+	machine.accumulator += operands[0] + operands[1]
+}
+
+func subHandler(machine *Machine, operands []int) {
+	machine.accumulator += operands[0] - operands[1]
+}
+
+func resetHandler(machine *Machine, operands []int) {
+	machine.accumulator = 0
+}
+
+func TestFirstAndSecondPart(t *testing.T) {
+	memory := loadMemory(DefaultTestInputFile)
+
+	// Same as multiplyAndSumPairs, 161 and 48
+	if result := firstPart(memory); result != 161 {
+		t.Errorf("Expected %d, but got %d", 161, result)
+	}
+	if result := secondPart(memory); result != 48 {
+		t.Errorf("Expected %d, but got %d", 48, result)
+	}
+}
+
+func TestInterpreter_RegisteredInstructions(t *testing.T) {
+	memory := "mul(2,3)add(4,5)?don't()sub(10,1)do()sub(1,10)xreset()mul(3,3)add(1,1)"
+
+	testValues := func(interpreter *Interpreter, memory string, expected int) {
+		machine := interpreter.run(memory)
+		if machine.accumulator != expected {
+			t.Errorf("Expected %d, but got %d", expected, machine.accumulator)
+		}
+	}
+
+	// Unregistered instructions aren't even tokens: 6 + 9
+	testValues(firstPartInterpreter(), memory, 15)
+
+	// 6 + 9 + 9 - 9, reset, then 9 + 2
+	interpreter := firstPartInterpreter()
+	interpreter.register("add", 2, addHandler)
+	interpreter.register("sub", 2, subHandler)
+	interpreter.register("reset", 0, resetHandler)
+	testValues(interpreter, memory, 11)
+
+	// sub(10,1) is skipped: 6 + 9 - 9, reset, then 9 + 2
+	interpreter = secondPartInterpreter()
+	interpreter.register("add", 2, addHandler)
+	interpreter.register("sub", 2, subHandler)
+	interpreter.register("reset", 0, resetHandler)
+	testValues(interpreter, memory, 11)
+
+	// A control instruction runs while disabled: reset() clears the 6, then only mul(1,2) counts
+	interpreter = secondPartInterpreter()
+	interpreter.register("reset", 0, resetHandler, true)
+	testValues(interpreter, "mul(2,3)don't()reset()mul(5,5)do()mul(1,2)", 2)
+
+	// The operand count comes from the registry
+	interpreter = firstPartInterpreter()
+	interpreter.register("reset", 0, resetHandler)
+	testValues(interpreter, "mul(2,3)reset(1)", 6)
+}
+
+func TestInterpreter_Step(t *testing.T) {
+	interpreter := secondPartInterpreter()
+	machine := interpreter.newMachine()
+
+	testValues := func(token Token, expectedExecuted bool, expectedAccumulator int, expectedEnabled bool) {
+		executed := interpreter.step(&machine, token)
+		if executed != expectedExecuted || machine.accumulator != expectedAccumulator || machine.enabled != expectedEnabled {
+			t.Errorf("Expected %t, %d, %t for %v, but got %t, %d, %t",
+				expectedExecuted, expectedAccumulator, expectedEnabled, token.Name,
+				executed, machine.accumulator, machine.enabled)
+		}
+	}
+
+	testValues(Token{Name: "mul", Operands: []int{2, 4}}, true, 8, true)
+	testValues(Token{Name: "don't"}, true, 8, false)
+	testValues(Token{Name: "mul", Operands: []int{5, 5}}, false, 8, false)
+	testValues(Token{Name: "jump"}, false, 8, false)
+	testValues(Token{Name: "do"}, true, 8, true)
+	testValues(Token{Name: "mul", Operands: []int{8, 5}}, true, 48, true)
+}
+
+func TestInterpreter_MatchesMultiplyAndSumPairs(t *testing.T) {
+	random := rand.New(rand.NewSource(20))
+	fragments := []string{"do()", "don't()", "mul(", ",", ")", "x", "]"}
+
+	for round := 0; round < 300; round++ {
+		var builder strings.Builder
+		for i := 0; i < 30; i++ {
+			builder.WriteString(fragments[random.Intn(len(fragments))])
+			builder.WriteString(strconv.Itoa(random.Intn(1000)))
+		}
+		memory := builder.String()
+
+		tokens, _ := newLexer(memory).tokenize()
+		pairs := pairsFromTokens(tokens)
+
+		if expected, result := multiplyAndSumPairs(pairs, false), firstPart(memory); result != expected {
+			t.Errorf("Expected %d, but got %d. Memory: %q", expected, result, memory)
+		}
+		if expected, result := multiplyAndSumPairs(pairs, true), secondPart(memory); result != expected {
+			t.Errorf("Expected %d, but got %d. Memory: %q", expected, result, memory)
+		}
+	}
+}
@@ -0,0 +1,272 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Instructions the lexer recognises, and how many operands each one takes
+var defaultInstructions = map[string]int{
+	"mul":   2,
+	"do":    0,
+	"don't": 0,
+}
+
+// The puzzle only accepts operands of 1 to 3 digits
+const maxOperandDigits = 3
+
+// Position class, Line and Column start at 1, Column counts bytes
+type Position struct {
+	Offset int
+	Line   int
+	Column int
+}
+
+// Token class, one valid instruction
+type Token struct {
+	Name     string
+	Operands []int
+	Text     string
+	Position Position
+}
+
+type NearMissReason int
+
+const (
+	// mul[3,7] or mul(32,64]
+	WrongBracket NearMissReason = iota
+	// mul(1234,5)
+	TooManyDigits
+	// mul(,5) or mul(3,)
+	MissingOperand
+	// mul(3) or do(1)
+	WrongOperandCount
+	// mul (2,4) or mul(2, 4)
+	UnexpectedSpace
+	// mul(2*4)
+	UnexpectedCharacter
+	// mul(2,4 at the end of the input
+	UnexpectedEnd
+)
+
+// NearMiss class, a fragment that starts like an instruction but isn't a valid one
+type NearMiss struct {
+	Text     string
+	Reason   NearMissReason
+	Position Position
+}
+
+// Lexer class
+type Lexer struct {
+	source       string
+	instructions map[string]int
+	// Longest names first, so don't() is tried before do()
+	names []string
+}
+
+func newLexer(source string, instructions ...map[string]int) *Lexer {
+	// Optional instructions replace the puzzle's mul, do and don't
+	selectedInstructions := defaultInstructions
+	if len(instructions) > 0 {
+		selectedInstructions = instructions[0]
+	}
+
+	names := make([]string, 0, len(selectedInstructions))
+	for name := range selectedInstructions {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i int, j int) bool {
+		if len(names[i]) != len(names[j]) {
+			return len(names[i]) > len(names[j])
+		}
+		return names[i] < names[j]
+	})
+
+	return &Lexer{source: source, instructions: selectedInstructions, names: names}
+}
+
+func pairsFromTokens(tokens []Token) (pairs []Pair) {
+	// Same as loadInputs: enabled at the start, then toggled by do() and don't()
+	enabled := true
+	for _, token := range tokens {
+		switch token.Name {
+		case "do":
+			enabled = true
+		case "don't":
+			enabled = false
+		case "mul":
+			pairs = append(pairs, Pair{token.Operands[0], token.Operands[1], enabled})
+		}
+	}
+	return pairs
+}
+
+// Lexer methods
+func (l *Lexer) tokenize() (tokens []Token, nearMisses []NearMiss) {
+	// Tries every offset, like the regex did, so do() is still found inside undo()
+	line, lineStart := 1, 0
+
+	for offset := 0; offset < len(l.source); {
+		position := Position{offset, line, offset - lineStart + 1}
+
+		token, nearMiss, _ := l.scanAt(offset)
+		if token != nil {
+			token.Position = position
+			tokens = append(tokens, *token)
+			// A valid instruction never spans a line
+			offset += len(token.Text)
+			continue
+		}
+
+		if nearMiss != nil {
+			nearMiss.Position = position
+			nearMisses = append(nearMisses, *nearMiss)
+		}
+
+		if l.source[offset] == '\n' {
+			line++
+			lineStart = offset + 1
+		}
+		offset++
+	}
+
+	return tokens, nearMisses
+}
+
+func (l *Lexer) scanAt(offset int) (token *Token, nearMiss *NearMiss, truncated bool) {
+	// Returns the instruction starting at offset, or the near miss, or neither.
+	// truncated is true when the source ends before the instruction could be decided.
+	for _, name := range l.names {
+		if !strings.HasPrefix(l.source[offset:], name) {
+			if strings.HasPrefix(name, l.source[offset:]) {
+				truncated = true
+			}
+			continue
+		}
+
+		token, nearMiss, nameTruncated := l.scanInstruction(offset, name)
+		if token != nil || nearMiss != nil {
+			return token, nearMiss, nameTruncated
+		}
+		truncated = truncated || nameTruncated
+	}
+
+	return nil, nil, truncated
+}
+
+func (l *Lexer) scanInstruction(start int, name string) (*Token, *NearMiss, bool) {
+	// The parse is lenient, so that the whole near miss is reported with its first problem
+	source := l.source
+	i := start + len(name)
+	reason, valid := NearMissReason(0), true
+	fail := func(why NearMissReason) {
+		if valid {
+			reason, valid = why, false
+		}
+	}
+
+	skipSpaces := func() {
+		for i < len(source) && (source[i] == ' ' || source[i] == '\t') {
+			fail(UnexpectedSpace)
+			i++
+		}
+	}
+
+	skipSpaces()
+	if i == len(source) {
+		return nil, nil, true
+	}
+
+	// Without an opening bracket, it's only a word that looks like an instruction
+	if !strings.ContainsRune("([{<", rune(source[i])) {
+		return nil, nil, false
+	}
+	if source[i] != '(' {
+		fail(WrongBracket)
+	}
+	i++
+
+	operands := []int{}
+	// Operands, unless the list is empty
+	skipSpaces()
+	if i == len(source) || !strings.ContainsRune(")]}>", rune(source[i])) {
+		for {
+			skipSpaces()
+			value, digits := 0, 0
+			for i < len(source) && source[i] >= '0' && source[i] <= '9' {
+				value = value*10 + int(source[i]-'0')
+				digits++
+				i++
+			}
+
+			if digits == 0 {
+				fail(MissingOperand)
+			} else if digits > maxOperandDigits {
+				fail(TooManyDigits)
+			}
+			operands = append(operands, value)
+
+			skipSpaces()
+			if i == len(source) {
+				return nil, &NearMiss{Text: source[start:i], Reason: UnexpectedEnd}, true
+			}
+			if source[i] != ',' {
+				break
+			}
+			i++
+		}
+	}
+
+	if i == len(source) {
+		return nil, &NearMiss{Text: source[start:i], Reason: UnexpectedEnd}, true
+	}
+	if !strings.ContainsRune(")]}>", rune(source[i])) {
+		// The unexpected character isn't part of the fragment
+		fail(UnexpectedCharacter)
+		return nil, &NearMiss{Text: source[start:i], Reason: reason}, false
+	}
+	if source[i] != ')' {
+		fail(WrongBracket)
+	}
+	i++
+
+	if len(operands) != l.instructions[name] {
+		fail(WrongOperandCount)
+	}
+	if !valid {
+		return nil, &NearMiss{Text: source[start:i], Reason: reason}, false
+	}
+
+	return &Token{Name: name, Operands: operands, Text: source[start:i]}, nil, false
+}
+
+// Position methods
+func (p Position) String() string {
+	return fmt.Sprintf("%d:%d", p.Line, p.Column)
+}
+
+// NearMissReason methods
+func (r NearMissReason) String() string {
+	switch r {
+	case WrongBracket:
+		return "wrong bracket"
+	case TooManyDigits:
+		return fmt.Sprintf("operand with more than %d digits", maxOperandDigits)
+	case MissingOperand:
+		return "missing operand"
+	case WrongOperandCount:
+		return "wrong number of operands"
+	case UnexpectedSpace:
+		return "unexpected space"
+	case UnexpectedCharacter:
+		return "unexpected character"
+	default:
+		return "unexpected end of input"
+	}
+}
+
+// NearMiss methods
+func (n NearMiss) String() string {
+	return fmt.Sprintf("%s: %q, %s", n.Position, n.Text, n.Reason)
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"math/rand"
+	"os"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func regexPairs(content string) (pairs []Pair) {
+	// The regex extraction loadInputs used before the lexer
+	re := regexp.MustCompile(`mul\((\d+),(\d+)\)|do\(\)|don't\(\)`)
+	enabled := true
+	for _, match := range re.FindAllStringSubmatch(content, -1) {
+		switch match[0] {
+		case "do()":
+			enabled = true
+		case "don't()":
+			enabled = false
+		default:
+			first, _ := strconv.Atoi(match[1])
+			second, _ := strconv.Atoi(match[2])
+			pairs = append(pairs, Pair{first, second, enabled})
+		}
+	}
+	return pairs
+}
+
+func TestLexer_Tokenize(t *testing.T) {
+	data, err := os.ReadFile(DefaultTestInputFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tokens, nearMisses := newLexer(string(data)).tokenize()
+
+	// do() is found inside undo()
+	expectedTokens := []Token{
+		{"mul", []int{2, 4}, "mul(2,4)", Position{1, 1, 2}},
+		{"don't", []int{}, "don't()", Position{20, 1, 21}},
+		{"mul", []int{5, 5}, "mul(5,5)", Position{28, 1, 29}},
+		{"mul", []int{11, 8}, "mul(11,8)", Position{48, 1, 49}},
+		{"do", []int{}, "do()", Position{59, 1, 60}},
+		{"mul", []int{8, 5}, "mul(8,5)", Position{64, 1, 65}},
+	}
+	if !reflect.DeepEqual(tokens, expectedTokens) {
+		t.Errorf("Expected %v, but got %v", expectedTokens, tokens)
+	}
+
+	expectedNearMisses := []NearMiss{
+		{"mul[3,7]", WrongBracket, Position{10, 1, 11}},
+		{"mul(32,64]", WrongBracket, Position{37, 1, 38}},
+	}
+	if !reflect.DeepEqual(nearMisses, expectedNearMisses) {
+		t.Errorf("Expected %v, but got %v", expectedNearMisses, nearMisses)
+	}
+
+	if pairs := pairsFromTokens(tokens); !reflect.DeepEqual(pairs, getTestPairs()) {
+		t.Errorf("Expected %v, but got %v", getTestPairs(), pairs)
+	}
+}
+
+func TestLexer_Positions(t *testing.T) {
+	tokens, nearMisses := newLexer("mul(1,2)\nab do()\n\n  mul(1234,5)").tokenize()
+
+	expectedPositions := []Position{{0, 1, 1}, {12, 2, 4}}
+	for i, token := range tokens {
+		if token.Position != expectedPositions[i] {
+			t.Errorf("Expected %v, but got %v", expectedPositions[i], token.Position)
+		}
+	}
+
+	if len(nearMisses) != 1 || nearMisses[0].Position != (Position{20, 4, 3}) {
+		t.Errorf("Expected a near miss at 4:3, but got %v", nearMisses)
+	}
+}
+
+func TestLexer_NearMisses(t *testing.T) {
+	testValues := func(source string, expectedText string, expectedReason NearMissReason) {
+		tokens, nearMisses := newLexer(source).tokenize()
+		if len(tokens) != 0 {
+			t.Errorf("Expected no token in %q, but got %v", source, tokens)
+		}
+		if len(nearMisses) != 1 || nearMisses[0].Text != expectedText || nearMisses[0].Reason != expectedReason {
+			t.Errorf("Expected %q (%v) in %q, but got %v", expectedText, expectedReason, source, nearMisses)
+		}
+	}
+
+	testValues("xmul(32,64]then", "mul(32,64]", WrongBracket)
+	testValues("mul{3,7}", "mul{3,7}", WrongBracket)
+	testValues("mul(1234,5)", "mul(1234,5)", TooManyDigits)
+	testValues("mul(,5)", "mul(,5)", MissingOperand)
+	testValues("mul(3)", "mul(3)", WrongOperandCount)
+	testValues("mul(1,2,3)", "mul(1,2,3)", WrongOperandCount)
+	testValues("do(1)", "do(1)", WrongOperandCount)
+	testValues("mul (2,4)", "mul (2,4)", UnexpectedSpace)
+	testValues("mul(2, 4)", "mul(2, 4)", UnexpectedSpace)
+	testValues("mul(2*4)", "mul(2", UnexpectedCharacter)
+	testValues("?mul(2,4", "mul(2,4", UnexpectedEnd)
+
+	// Only words followed by a bracket are attempts
+	if _, nearMisses := newLexer("multiply don'tdo mul").tokenize(); len(nearMisses) != 0 {
+		t.Errorf("Expected no near miss, but got %v", nearMisses)
+	}
+}
+
+func TestLexer_MatchesRegex(t *testing.T) {
+	// Random memory with operands of at most 3 digits, so both extractions must agree
+	random := rand.New(rand.NewSource(19))
+	fragments := []string{"mul(", "do()", "don't()", "don't", "do(", ")", "(", ",", "]", "mu", "l", "x", " ", "\n", "?"}
+
+	for round := 0; round < 500; round++ {
+		var builder strings.Builder
+		lastWasNumber := false
+		for i := 0; i < 40; i++ {
+			// Two numbers in a row would make an operand of more than 3 digits
+			if random.Intn(3) == 0 && !lastWasNumber {
+				builder.WriteString(strconv.Itoa(random.Intn(1000)))
+				lastWasNumber = true
+			} else {
+				builder.WriteString(fragments[random.Intn(len(fragments))])
+				lastWasNumber = false
+			}
+		}
+		content := builder.String()
+
+		tokens, _ := newLexer(content).tokenize()
+		expected, pairs := regexPairs(content), pairsFromTokens(tokens)
+		if !reflect.DeepEqual(pairs, expected) {
+			t.Errorf("Expected %v, but got %v. Memory: %q", expected, pairs, content)
+		}
+	}
+}
@@ -1,10 +1,9 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"os"
-	"regexp"
-	"strconv"
 )
 
 // Pair class
@@ -15,19 +14,113 @@ type Pair struct {
 }
 
 func main() {
-	pairs := loadInputs("inputs.txt")
+	nearMisses := flag.Bool("nearmisses", false, "print the fragments that look like instructions but are not valid")
+	chunkSize := flag.Int("chunk", 0, "stream the input in chunks of this many bytes, instead of loading it whole")
+	render := flag.String("render", "", "print the memory with its instructions marked, as plain, ansi or html")
+	subtotals := flag.Bool("subtotals", false, "with -render, print the sum of the counted instructions at the end of each line")
+	controlFlowName := flag.String("controlflow", "toggle", "how do() and don't() nest in the second part: toggle, stack or counted")
+	scopes := flag.Bool("scopes", false, "print the scope depth after every instruction of the second part")
+	trace := flag.Bool("trace", false, "print one line per valid instruction, with the running sums of both parts")
+	decompiled := flag.String("decompile", "", "write the valid instructions to this file, one per line")
+	generate := flag.String("generate", "", "write random corrupted memory to this file, and print its expected answers")
+	seed := flag.Int64("seed", 1, "with -generate, the seed of the random memory")
+	flag.Parse()
+
+	if *generate != "" {
+		options := defaultGeneratorOptions()
+		options.Seed = *seed
+		generated := generateMemory(options)
+
+		if err := os.WriteFile(*generate, []byte(generated.memory), 0644); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		fmt.Println("Expected first part solution: ", generated.firstPart)
+		fmt.Println("Expected second part solution: ", generated.secondPart)
+		return
+	}
+
+	controlFlows := map[string]ControlFlowMode{"toggle": ToggleControlFlow, "stack": StackControlFlow, "counted": CountedControlFlow}
+	controlFlow, ok := controlFlows[*controlFlowName]
+	if !ok {
+		fmt.Println("unknown control flow:", *controlFlowName)
+		os.Exit(1)
+	}
+
+	if *chunkSize > 0 {
+		file, err := os.Open("inputs.txt")
+		if err != nil {
+			os.Exit(1)
+		}
+		defer file.Close()
+
+		totals, err := scanStream(file, *chunkSize, controlFlow)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		fmt.Println("First part solution: ", totals.firstPart)
+		fmt.Println("Second part solution: ", totals.secondPart)
+		return
+	}
+
+	memory := loadMemory("inputs.txt")
+
+	if *nearMisses {
+		_, fragments := newLexer(memory).tokenize()
+		for _, fragment := range fragments {
+			fmt.Println(fragment)
+		}
+	}
+
+	if *render != "" {
+		styles := map[string]RenderStyle{"plain": PlainStyle, "ansi": ANSIStyle, "html": HTMLStyle}
+		style, ok := styles[*render]
+		if !ok {
+			fmt.Println("unknown render style:", *render)
+			os.Exit(1)
+		}
+
+		fmt.Println(renderMemory(memory, RenderOptions{Style: style, Subtotals: *subtotals, ControlFlow: controlFlow}))
+	}
+
+	if *scopes {
+		fmt.Print(scopeDump(secondPartInterpreter(controlFlow).traceScopes(memory)))
+	}
+
+	if *trace {
+		fmt.Print(traceText(traceMemory(memory, controlFlow)))
+	}
+
+	if *decompiled != "" {
+		if err := os.WriteFile(*decompiled, []byte(decompile(memory)), 0644); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	}
 
 	// print first part solution
-	fmt.Println("First part solution: ", firstPart(pairs))
-	fmt.Println("Second part solution: ", secondPart(pairs))
+	fmt.Println("First part solution: ", firstPart(memory))
+	fmt.Println("Second part solution: ", secondPart(memory, controlFlow))
+}
+
+func firstPart(memory string) int {
+	return firstPartInterpreter().run(memory).accumulator
 }
 
-func firstPart(pairs []Pair) int {
-	return multiplyAndSumPairs(pairs, false)
+func secondPart(memory string, controlFlow ...ControlFlowMode) int {
+	return secondPartInterpreter(controlFlow...).run(memory).accumulator
 }
 
-func secondPart(pairs []Pair) int {
-	return multiplyAndSumPairs(pairs, true)
+func loadMemory(filename string) string {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		os.Exit(1)
+	}
+
+	return string(data)
 }
 
 func loadInputs(filename string) (pairs []Pair) {
@@ -38,48 +131,10 @@ func loadInputs(filename string) (pairs []Pair) {
 	// in this case, the expected pairs are :
 	// Pair(2,4, true) ; Pair(5,5, false) ; Pair(11,8, false) ; Pair(8,5, true)
 
-	data, err := os.ReadFile(filename)
-	if err != nil {
-		os.Exit(1)
-	}
-
-	fileContent := string(data)
-
-	re := regexp.MustCompile(`mul\(\d+,\d+\)|do\(\)|don't\(\)`)
-	parts := re.FindAllString(fileContent, -1)
-	// Always start with enabled
-	enabled := true
-	for _, part := range parts {
-		// When we see do() or don't(), we change the enabled flag
-		if part == "do()" {
-			enabled = true
-			continue
-		} else if part == "don't()" {
-			enabled = false
-			continue
-		}
-
-		// Add mul() pairs
-		re = regexp.MustCompile(`\d+`)
-		matches := re.FindAllString(part, -1)
-
-		first, err := strconv.Atoi(matches[0])
-		if err != nil {
-			continue
-		}
-
-		second, err := strconv.Atoi(matches[1])
-		if err != nil {
-			continue
-		}
-
-		pairs = append(
-			pairs,
-			Pair{first, second, enabled},
-		)
-	}
+	// Only the valid instructions make pairs, near misses are ignored
+	tokens, _ := newLexer(loadMemory(filename)).tokenize()
 
-	return pairs
+	return pairsFromTokens(tokens)
 }
 
 func multiplyAndSumPairs(pairs []Pair, excludeDisabled bool) int {
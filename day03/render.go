@@ -0,0 +1,212 @@
+package main
+
+import (
+	"fmt"
+	"html"
+	"sort"
+	"strings"
+)
+
+type RenderStyle int
+
+const (
+	// Markers around each fragment, readable without a terminal
+	PlainStyle RenderStyle = iota
+	ANSIStyle
+	HTMLStyle
+)
+
+type Annotation int
+
+const (
+	// mul counted in the second part
+	CountedInstruction Annotation = iota
+	// mul skipped because of don't()
+	SkippedInstruction
+	// do() or don't()
+	ControlInstruction
+	NearMissFragment
+)
+
+// RenderOptions class
+type RenderOptions struct {
+	Style RenderStyle
+	// Sum of the counted instructions at the end of each line
+	Subtotals bool
+	// How do() and don't() nest when deciding which instructions count
+	ControlFlow ControlFlowMode
+}
+
+// annotatedSpan class, an annotated fragment of the memory
+type annotatedSpan struct {
+	start        int
+	end          int
+	annotation   Annotation
+	contribution int
+}
+
+func renderMemory(memory string, options ...RenderOptions) string {
+	// The original memory, with every instruction and near miss marked
+	selectedOptions := RenderOptions{}
+	if len(options) > 0 {
+		selectedOptions = options[0]
+	}
+	style := selectedOptions.Style
+
+	tokens, nearMisses := newLexer(memory).tokenize()
+	spans := []annotatedSpan{}
+
+	// Replays the second part to know which instructions count
+	interpreter := secondPartInterpreter(selectedOptions.ControlFlow)
+	machine := interpreter.newMachine()
+	for _, token := range tokens {
+		before := machine.accumulator
+		executed := interpreter.step(&machine, token)
+
+		annotation := CountedInstruction
+		if interpreter.instructions[token.Name].control {
+			annotation = ControlInstruction
+		} else if !executed {
+			annotation = SkippedInstruction
+		}
+
+		start := token.Position.Offset
+		spans = append(spans, annotatedSpan{start, start + len(token.Text), annotation, machine.accumulator - before})
+	}
+
+	for _, nearMiss := range nearMisses {
+		start := nearMiss.Position.Offset
+		spans = append(spans, annotatedSpan{start, start + len(nearMiss.Text), NearMissFragment, 0})
+	}
+
+	sort.SliceStable(spans, func(i int, j int) bool {
+		return spans[i].start < spans[j].start
+	})
+
+	var builder strings.Builder
+	builder.WriteString(style.header())
+
+	lineTotal := 0
+	writeText := func(text string) {
+		// Spans never hold a newline, so lines only end in the text between them
+		for {
+			newline := strings.IndexByte(text, '\n')
+			if newline == -1 {
+				builder.WriteString(style.escape(text))
+				return
+			}
+
+			builder.WriteString(style.escape(text[:newline]))
+			if selectedOptions.Subtotals {
+				builder.WriteString(style.subtotal(lineTotal))
+			}
+			builder.WriteString("\n")
+			lineTotal = 0
+			text = text[newline+1:]
+		}
+	}
+
+	cursor := 0
+	for _, span := range spans {
+		// Near misses may overlap, the first one wins
+		if span.start < cursor {
+			continue
+		}
+
+		writeText(memory[cursor:span.start])
+		builder.WriteString(style.open(span.annotation))
+		builder.WriteString(style.escape(memory[span.start:span.end]))
+		builder.WriteString(style.close())
+
+		lineTotal += span.contribution
+		cursor = span.end
+	}
+	writeText(memory[cursor:])
+
+	// The last line, unless the memory ends with a newline
+	if selectedOptions.Subtotals && len(memory) > 0 && !strings.HasSuffix(memory, "\n") {
+		builder.WriteString(style.subtotal(lineTotal))
+	}
+
+	builder.WriteString(style.footer())
+	return builder.String()
+}
+
+// RenderStyle methods
+func (s RenderStyle) open(annotation Annotation) string {
+	switch s {
+	case ANSIStyle:
+		return map[Annotation]string{
+			CountedInstruction: "\x1b[32m",
+			SkippedInstruction: "\x1b[2m",
+			ControlInstruction: "\x1b[36m",
+			NearMissFragment:   "\x1b[31m",
+		}[annotation]
+	case HTMLStyle:
+		return fmt.Sprintf(`<span class="%s">`, annotation)
+	default:
+		return map[Annotation]string{
+			CountedInstruction: "[+",
+			SkippedInstruction: "[-",
+			ControlInstruction: "[=",
+			NearMissFragment:   "[?",
+		}[annotation]
+	}
+}
+
+func (s RenderStyle) close() string {
+	switch s {
+	case ANSIStyle:
+		return "\x1b[0m"
+	case HTMLStyle:
+		return "</span>"
+	default:
+		return "]"
+	}
+}
+
+func (s RenderStyle) escape(text string) string {
+	if s == HTMLStyle {
+		return html.EscapeString(text)
+	}
+	return text
+}
+
+func (s RenderStyle) subtotal(total int) string {
+	switch s {
+	case ANSIStyle:
+		return fmt.Sprintf("\x1b[33m  => %d\x1b[0m", total)
+	case HTMLStyle:
+		return fmt.Sprintf(`<span class="subtotal">  =&gt; %d</span>`, total)
+	default:
+		return fmt.Sprintf("  => %d", total)
+	}
+}
+
+func (s RenderStyle) header() string {
+	if s == HTMLStyle {
+		return `<pre class="memory">`
+	}
+	return ""
+}
+
+func (s RenderStyle) footer() string {
+	if s == HTMLStyle {
+		return "</pre>\n"
+	}
+	return ""
+}
+
+// Annotation methods
+func (a Annotation) String() string {
+	switch a {
+	case CountedInstruction:
+		return "counted"
+	case SkippedInstruction:
+		return "skipped"
+	case ControlInstruction:
+		return "control"
+	default:
+		return "near-miss"
+	}
+}
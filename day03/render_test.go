@@ -0,0 +1,72 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderMemory(t *testing.T) {
+	memory := loadMemory(DefaultTestInputFile)
+
+	expected := "x[+mul(2,4)]&[?mul[3,7]]!^[=don't()]_[-mul(5,5)]+[?mul(32,64]](" +
+		"[-mul(11,8)]un[=do()]?[+mul(8,5)])"
+
+	if result := renderMemory(memory); result != expected {
+		t.Errorf("Expected %q, but got %q", expected, result)
+	}
+}
+
+func TestRenderMemory_Subtotals(t *testing.T) {
+	memory := "mul(2,4)don't()\nmul(3,3)\n\ndo()mul(1,2)x mul(5,5)"
+
+	testValues := func(options RenderOptions, expected string) {
+		if result := renderMemory(memory, options); result != expected {
+			t.Errorf("Expected %q, but got %q", expected, result)
+		}
+	}
+
+	testValues(RenderOptions{Subtotals: true}, strings.Join([]string{
+		"[+mul(2,4)][=don't()]  => 8",
+		"[-mul(3,3)]  => 0",
+		"  => 0",
+		"[=do()][+mul(1,2)]x [+mul(5,5)]  => 27",
+	}, "\n"))
+
+	// A trailing newline doesn't add an empty line
+	if result := renderMemory("mul(2,4)\n", RenderOptions{Subtotals: true}); result != "[+mul(2,4)]  => 8\n" {
+		t.Errorf("Expected %q, but got %q", "[+mul(2,4)]  => 8\n", result)
+	}
+}
+
+func TestRenderMemory_Styles(t *testing.T) {
+	memory := "<mul(2,4)don't()mul[1,2]"
+
+	html := renderMemory(memory, RenderOptions{Style: HTMLStyle, Subtotals: true})
+	expectedHTML := `<pre class="memory">&lt;<span class="counted">mul(2,4)</span>` +
+		`<span class="control">don&#39;t()</span><span class="near-miss">mul[1,2]</span>` +
+		`<span class="subtotal">  =&gt; 8</span></pre>` + "\n"
+	if html != expectedHTML {
+		t.Errorf("Expected %q, but got %q", expectedHTML, html)
+	}
+
+	ansi := renderMemory(memory, RenderOptions{Style: ANSIStyle})
+	expectedANSI := "<\x1b[32mmul(2,4)\x1b[0m\x1b[36mdon't()\x1b[0m\x1b[31mmul[1,2]\x1b[0m"
+	if ansi != expectedANSI {
+		t.Errorf("Expected %q, but got %q", expectedANSI, ansi)
+	}
+}
+
+func TestRenderMemory_ControlFlow(t *testing.T) {
+	// The stack mode needs two do() to close the two scopes, so mul(3,3) is skipped
+	memory := "mul(1,1)don't()don't()do()mul(3,3)do()mul(4,4)"
+
+	testValues := func(controlFlow ControlFlowMode, expected string) {
+		if result := renderMemory(memory, RenderOptions{Subtotals: true, ControlFlow: controlFlow}); result != expected {
+			t.Errorf("Expected %q for mode %d, but got %q", expected, controlFlow, result)
+		}
+	}
+
+	testValues(ToggleControlFlow, "[+mul(1,1)][=don't()][=don't()][=do()][+mul(3,3)][=do()][+mul(4,4)]  => 26")
+	testValues(StackControlFlow, "[+mul(1,1)][=don't()][=don't()][=do()][-mul(3,3)][=do()][+mul(4,4)]  => 17")
+	testValues(CountedControlFlow, "[+mul(1,1)][=don't()][=don't()][=do()][-mul(3,3)][=do()][+mul(4,4)]  => 17")
+}
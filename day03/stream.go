@@ -0,0 +1,100 @@
+package main
+
+import (
+	"io"
+)
+
+const defaultChunkSize = 64 * 1024
+
+// StreamTotals class, running totals of both parts
+type StreamTotals struct {
+	firstPart    int
+	secondPart   int
+	enabled      bool
+	instructions int
+}
+
+func scanStream(input io.Reader, chunkSize int, controlFlow ...ControlFlowMode) (StreamTotals, error) {
+	// Same as firstPart and secondPart in a single pass, without keeping the memory or the pairs
+	first, second := firstPartInterpreter(), secondPartInterpreter(controlFlow...)
+	firstMachine, secondMachine := first.newMachine(), second.newMachine()
+	instructions := 0
+
+	err := scanChunks(input, chunkSize, defaultInstructions, func(token Token) {
+		first.step(&firstMachine, token)
+		second.step(&secondMachine, token)
+		instructions++
+	})
+
+	return StreamTotals{
+		firstPart:    firstMachine.accumulator,
+		secondPart:   secondMachine.accumulator,
+		enabled:      secondMachine.enabled,
+		instructions: instructions,
+	}, err
+}
+
+func scanChunks(input io.Reader, chunkSize int, instructions map[string]int, visit func(Token)) error {
+	// Reads chunkSize bytes at a time. An instruction that could still be completed by the next chunk
+	// is carried over, so only the carry and one chunk are in memory.
+	if chunkSize <= 0 {
+		chunkSize = defaultChunkSize
+	}
+
+	buffer := make([]byte, chunkSize)
+	lexer := newLexer("", instructions)
+	longest := maxTokenLength(instructions)
+
+	// base is the offset of the carry in the whole input
+	carry, base := "", 0
+	line, lineStart := 1, 0
+
+	for atEnd := false; !atEnd; {
+		read, err := input.Read(buffer)
+		if err == io.EOF {
+			atEnd = true
+		} else if err != nil {
+			return err
+		}
+
+		lexer.source = carry + string(buffer[:read])
+
+		offset := 0
+		for offset < len(lexer.source) {
+			token, _, truncated := lexer.scanAt(offset)
+
+			// Wait for the next chunk, unless no valid instruction can be that long
+			if truncated && !atEnd && len(lexer.source)-offset < longest {
+				break
+			}
+
+			if token != nil {
+				token.Position = Position{base + offset, line, base + offset - lineStart + 1}
+				visit(*token)
+				offset += len(token.Text)
+				continue
+			}
+
+			if lexer.source[offset] == '\n' {
+				line++
+				lineStart = base + offset + 1
+			}
+			offset++
+		}
+
+		carry = lexer.source[offset:]
+		base += offset
+	}
+
+	return nil
+}
+
+func maxTokenLength(instructions map[string]int) int {
+	// name(999,999)
+	longest := 0
+	for name, operands := range instructions {
+		length := len(name) + len("()") + operands*maxOperandDigits + max(operands-1, 0)
+		longest = max(longest, length)
+	}
+	return longest
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"math/rand"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// splitReader class, returns its pieces one Read at a time
+type splitReader struct {
+	pieces []string
+}
+
+func (r *splitReader) Read(buffer []byte) (int, error) {
+	if len(r.pieces) == 0 {
+		return 0, io.EOF
+	}
+
+	read := copy(buffer, r.pieces[0])
+	r.pieces[0] = r.pieces[0][read:]
+	if len(r.pieces[0]) == 0 {
+		r.pieces = r.pieces[1:]
+	}
+	return read, nil
+}
+
+func TestScanStream(t *testing.T) {
+	memory := loadMemory(DefaultTestInputFile)
+
+	// Every chunk size, from 1 byte to the whole input at once
+	for chunkSize := 1; chunkSize <= len(memory)+1; chunkSize++ {
+		totals, err := scanStream(strings.NewReader(memory), chunkSize)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		expected := StreamTotals{firstPart: 161, secondPart: 48, enabled: true, instructions: 6}
+		if totals != expected {
+			t.Errorf("Expected %+v with chunks of %d, but got %+v", expected, chunkSize, totals)
+		}
+	}
+}
+
+func TestScanStream_BoundaryInsideInstruction(t *testing.T) {
+	// A boundary at every offset, so inside every instruction and every near miss
+	memory := "xmul(123,456)don't()mul(2,3)undo()mul (1,1)mul(4,5]mul(7,8"
+
+	for boundary := 0; boundary <= len(memory); boundary++ {
+		reader := &splitReader{[]string{memory[:boundary], memory[boundary:]}}
+		totals, err := scanStream(reader, len(memory))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		expected := StreamTotals{firstPart: 123*456 + 2*3, secondPart: 123 * 456, enabled: true, instructions: 4}
+		if totals != expected {
+			t.Errorf("Expected %+v with a boundary at %d, but got %+v", expected, boundary, totals)
+		}
+	}
+}
+
+func TestScanChunks_Positions(t *testing.T) {
+	memory := "mul(1,2)\nab do()\n\n  mul(3,4)"
+	expected, _ := newLexer(memory).tokenize()
+
+	for chunkSize := 1; chunkSize <= len(memory); chunkSize++ {
+		tokens := []Token{}
+		err := scanChunks(strings.NewReader(memory), chunkSize, defaultInstructions, func(token Token) {
+			tokens = append(tokens, token)
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for i := range expected {
+			if tokens[i].Position != expected[i].Position {
+				t.Errorf("Expected %v with chunks of %d, but got %v", expected[i].Position, chunkSize, tokens[i].Position)
+			}
+		}
+	}
+}
+
+func TestScanStream_LongNearMiss(t *testing.T) {
+	// The carry stops growing once no instruction can be that long
+	memory := "mul(" + strings.Repeat("1", 10000) + ",2)mul(2,3)"
+
+	totals, err := scanStream(strings.NewReader(memory), 16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if totals.firstPart != 6 {
+		t.Errorf("Expected %d, but got %d", 6, totals.firstPart)
+	}
+}
+
+func TestScanStream_ReadError(t *testing.T) {
+	failing := errors.New("disk error")
+	_, err := scanStream(io.MultiReader(strings.NewReader("mul(2,3)"), &failingReader{failing}), 4)
+	if !errors.Is(err, failing) {
+		t.Errorf("Expected %v, but got %v", failing, err)
+	}
+}
+
+// failingReader class
+type failingReader struct {
+	err error
+}
+
+func (r *failingReader) Read(buffer []byte) (int, error) {
+	return 0, r.err
+}
+
+func TestScanStream_MatchesParts(t *testing.T) {
+	random := rand.New(rand.NewSource(21))
+	fragments := []string{"do()", "don't()", "mul(", ",", ")", "x", "\n", " "}
+
+	for round := 0; round < 200; round++ {
+		var builder strings.Builder
+		for i := 0; i < 30; i++ {
+			builder.WriteString(fragments[random.Intn(len(fragments))])
+			builder.WriteString(strconv.Itoa(random.Intn(1000)))
+		}
+		memory := builder.String()
+
+		chunkSize := 1 + random.Intn(20)
+		totals, err := scanStream(strings.NewReader(memory), chunkSize)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if totals.firstPart != firstPart(memory) || totals.secondPart != secondPart(memory) {
+			t.Errorf("Expected %d and %d with chunks of %d, but got %+v. Memory: %q",
+				firstPart(memory), secondPart(memory), chunkSize, totals, memory)
+		}
+	}
+}
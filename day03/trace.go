@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// TraceLine class, the machines after one instruction
+type TraceLine struct {
+	offset   int
+	name     string
+	operands []int
+	// Second part state, mul only counts when enabled
+	enabled bool
+	// Running sums of both parts
+	firstSum  int
+	secondSum int
+}
+
+func traceMemory(memory string, controlFlow ...ControlFlowMode) []TraceLine {
+	// One line per valid instruction, in the order they run
+	first, second := firstPartInterpreter(), secondPartInterpreter(controlFlow...)
+	firstMachine, secondMachine := first.newMachine(), second.newMachine()
+
+	tokens, _ := newLexer(memory).tokenize()
+	lines := []TraceLine{}
+	for _, token := range tokens {
+		// A mul runs with the state it finds, do() and don't() show the state they leave
+		enabled := secondMachine.enabled
+		first.step(&firstMachine, token)
+		second.step(&secondMachine, token)
+		if second.instructions[token.Name].control {
+			enabled = secondMachine.enabled
+		}
+
+		lines = append(lines, TraceLine{
+			offset:    token.Position.Offset,
+			name:      token.Name,
+			operands:  token.Operands,
+			enabled:   enabled,
+			firstSum:  firstMachine.accumulator,
+			secondSum: secondMachine.accumulator,
+		})
+	}
+	return lines
+}
+
+func traceText(lines []TraceLine) string {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "%8s %-6s %-8s %-8s %10s %10s\n", "offset", "instr", "operands", "state", "first", "second")
+
+	for _, line := range lines {
+		operands := make([]string, len(line.operands))
+		for i, operand := range line.operands {
+			operands[i] = strconv.Itoa(operand)
+		}
+
+		state := "enabled"
+		if !line.enabled {
+			state = "disabled"
+		}
+
+		fmt.Fprintf(&builder, "%8d %-6s %-8s %-8s %10d %10d\n",
+			line.offset, line.name, strings.Join(operands, ","), state, line.firstSum, line.secondSum)
+	}
+	return builder.String()
+}
+
+func decompile(memory string) string {
+	// Only the valid instructions, one per line, so that no new instruction can appear between them
+	tokens, _ := newLexer(memory).tokenize()
+
+	var builder strings.Builder
+	for _, token := range tokens {
+		builder.WriteString(token.Text)
+		builder.WriteString("\n")
+	}
+	return builder.String()
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"math/rand"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestTraceMemory(t *testing.T) {
+	lines := traceMemory(loadMemory(DefaultTestInputFile))
+
+	expected := "  offset instr  operands state         first     second\n" +
+		"       1 mul    2,4      enabled           8          8\n" +
+		"      20 don't           disabled          8          8\n" +
+		"      28 mul    5,5      disabled         33          8\n" +
+		"      48 mul    11,8     disabled        121          8\n" +
+		"      59 do              enabled         121          8\n" +
+		"      64 mul    8,5      enabled         161         48\n"
+
+	if result := traceText(lines); result != expected {
+		t.Errorf("Expected %q, but got %q", expected, result)
+	}
+}
+
+func TestDecompile(t *testing.T) {
+	memory := loadMemory(DefaultTestInputFile)
+
+	expected := "mul(2,4)\ndon't()\nmul(5,5)\nmul(11,8)\ndo()\nmul(8,5)\n"
+	program := decompile(memory)
+	if program != expected {
+		t.Errorf("Expected %q, but got %q", expected, program)
+	}
+
+	// The clean program is its own decompilation
+	if result := decompile(program); result != program {
+		t.Errorf("Expected %q, but got %q", program, result)
+	}
+}
+
+func TestDecompile_SameParts(t *testing.T) {
+	random := rand.New(rand.NewSource(24))
+	fragments := []string{"do()", "don't()", "mul(", "mul[", "un", "mu", "l(", ",", ")", "\n", " "}
+
+	for round := 0; round < 300; round++ {
+		var builder strings.Builder
+		for i := 0; i < 30; i++ {
+			builder.WriteString(fragments[random.Intn(len(fragments))])
+			if random.Intn(2) == 0 {
+				builder.WriteString(strconv.Itoa(random.Intn(1000)))
+			}
+		}
+		memory := builder.String()
+		program := decompile(memory)
+
+		if firstPart(program) != firstPart(memory) || secondPart(program) != secondPart(memory) {
+			t.Errorf("Expected %d and %d, but got %d and %d. Memory: %q",
+				firstPart(memory), secondPart(memory), firstPart(program), secondPart(program), memory)
+		}
+
+		for _, controlFlow := range []ControlFlowMode{StackControlFlow, CountedControlFlow} {
+			if secondPart(program, controlFlow) != secondPart(memory, controlFlow) {
+				t.Errorf("Expected %d for mode %d, but got %d. Memory: %q",
+					secondPart(memory, controlFlow), controlFlow, secondPart(program, controlFlow), memory)
+			}
+		}
+	}
+}